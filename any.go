@@ -62,26 +62,32 @@ func (cli *anyClient) decodeAny(in interface{}, outVal reflect.Value) error {
 	}
 
 	// If there are decoding hooks defined, process them.
-	if len(cli.options.hooks) > 0 {
-		// Execute the hook, and if it returns an error, stop the process.
-		for _, hook := range cli.options.hooks {
-			status, err := hook(in, outVal)
-			if err != nil {
-				return err
-			}
-			if status == DecodeSkip { // if hook return skip, skip decode
-				return nil
-			}
-			if status == DecodeStop { // if hook return stop, stop decode
-				return ErrDecodeStop
-			}
-		}
+	status, err := cli.runHooks(in, outVal)
+	if err != nil {
+		return err
+	}
+	if status == DecodeSkip { // if hook return skip, skip decode
+		return nil
+	}
+	if status == DecodeStop { // if hook return stop, stop decode
+		return ErrDecodeStop
+	}
+
+	// A pointer, map or slice that holds itself is decoded by the cycle policy.
+	done, release, err := cli.decodeRef(in, outVal)
+	if err != nil || done {
+		return err
+	}
+	if release != nil {
+		defer release()
 	}
 
 	// Based on the kind of the output value, call the appropriate decoding function.
 	outKind := outVal.Kind()
-	var err error
 	switch {
+	case outVal.Type() == durationType:
+		// time.Duration is an int64, but is parsed from duration strings and units.
+		err = cli.decodeDuration(in, outVal)
 	case isBasicType(outKind):
 		err = cli.decodeBasic(in, outVal)
 		if cli.options.ignoreBasicTypeErr {
@@ -92,12 +98,22 @@ func (cli *anyClient) decodeAny(in interface{}, outVal reflect.Value) error {
 	case outKind == reflect.Map:
 		err = cli.decodeMap(in, outVal)
 	case outKind == reflect.Struct:
-		// Special case for time.Time type which requires specific handling.
+		// Special case for time types which require specific handling.
 		switch outVal.Interface().(type) {
 		case time.Time:
 			err = cli.decodeTime(in, outVal)
+		case Date:
+			err = cli.decodeDate(in, outVal)
+		case TimeOfDay:
+			err = cli.decodeTimeOfDay(in, outVal)
+		case TimeRange:
+			err = cli.decodeTimeRange(in, outVal)
 		default:
-			err = cli.decodeStruct(in, outVal)
+			if outVal.Type().ConvertibleTo(timeReflectType) {
+				err = cli.decodeNamedTime(in, outVal)
+			} else {
+				err = cli.decodeStruct(in, outVal)
+			}
 		}
 	case outKind == reflect.Slice || outKind == reflect.Array:
 		err = cli.decodeList(in, outVal)
@@ -112,15 +128,29 @@ func (cli *anyClient) decodeAny(in interface{}, outVal reflect.Value) error {
 	return nil
 }
 
+// runHooks executes the decoding hooks in order. It returns the first status that is not
+// DecodeContinue, or the first error, so the caller can skip or stop the decoding.
+func (cli *anyClient) runHooks(in interface{}, outVal reflect.Value) (int, error) {
+	for _, hook := range cli.options.hooks {
+		status, err := hook(in, outVal)
+		if err != nil || status != DecodeContinue {
+			return status, err
+		}
+	}
+	return DecodeContinue, nil
+}
+
 // decodeInterface handles the decoding of an interface value into the provided output value.
 // The function works as follows:
 //  1. If the output value (outVal) is valid and not nil, it creates a new instance of the type
 //     that outVal represents, decodes the input into it, and sets the outVal with the new instance.
-//  2. If the options are set to export detailed information and the input is a struct,
+//  2. If the time export mode is set and the input is a time, the exported time is set, see exportTime.
+//  3. If the options are set to export detailed information and the input is a struct,
 //     it converts the input struct into a map with string keys and interface{} values,
 //     allowing for more detailed introspection of struct fields.
-//  3. If the output value is assignable from the input value's type, it directly assigns the input to the output.
-//  4. If the output value is not assignable, it returns an error indicating that the
+//  4. If the output value is assignable from the input value's type, it directly assigns the input to the output,
+//     or a deep copy of it if the deep copy option is set.
+//  5. If the output value is not assignable, it returns an error indicating that the
 //     input value cannot be assigned to the output interface.
 func (cli *anyClient) decodeInterface(in interface{}, outVal reflect.Value) error {
 	// Check if outVal is valid and has a non-nil underlying value.
@@ -137,6 +167,12 @@ func (cli *anyClient) decodeInterface(in interface{}, outVal reflect.Value) erro
 	inVal := reflect.ValueOf(in)
 	inValElem := reflect.Indirect(inVal)
 
+	// Times are exported in the time export mode of the options.
+	if v, ok := exportTime(inValElem.Interface(), *cli.options); ok && reflect.TypeOf(v).AssignableTo(outVal.Type()) {
+		outVal.Set(reflect.ValueOf(v))
+		return nil
+	}
+
 	// If exporting detailed struct information is enabled and the input is a struct,
 	// convert it into a map for detailed introspection.
 	if cli.options.structToMapDetail && inValElem.Kind() == reflect.Struct {
@@ -147,10 +183,26 @@ func (cli *anyClient) decodeInterface(in interface{}, outVal reflect.Value) erro
 		}
 		outVal.Set(currentValue)
 	} else {
+		// Numbers of json strings are float64 in interfaces, as encoding/json decodes them.
+		if cli.state.jsonNumbers > 0 && !cli.options.useNumber {
+			if v := reflect.ValueOf(jsonNumbersToFloat(in)); v.Type().AssignableTo(outVal.Type()) {
+				inVal = v
+			}
+		}
 		// Check if the input value's type is assignable to the output value's type.
 		if !inVal.Type().AssignableTo(outVal.Type()) {
 			return errors.Errorf(ErrInToOut, in, "interface")
 		}
+		if err := cli.checkAssigned(inVal); err != nil {
+			return err
+		}
+		if cli.options.deepCopy {
+			copied, err := cli.deepCopy(inVal)
+			if err != nil {
+				return err
+			}
+			inVal = copied
+		}
 		reflect.NewAt(outVal.Type(), unsafe.Pointer(outVal.UnsafeAddr())).Elem().Set(inVal)
 	}
 	return nil
@@ -177,17 +229,24 @@ func (cli *anyClient) decodePtr(in interface{}, outVal reflect.Value) error {
 	return nil
 }
 
+// stringToAny decodes a json string into the output value. Numbers are kept as json.Number
+// so that integers above 2^53 do not lose precision before they reach the basic converters,
+// the numbers that are set to interfaces are float64 unless the use number option is set.
 func (cli *anyClient) stringToAny(in interface{}, outVal reflect.Value) error {
 	inBytes := []byte(reflect.ValueOf(in).String())
 	if string(inBytes) == "" { //if in is empty, return nil
 		return nil
 	}
+	if err := cli.checkInputSize(len(inBytes)); err != nil {
+		return err
+	}
 	// check if the in is valid json
 	if !json.Valid(inBytes) {
 		return errors.Errorf(ErrNotJson, in)
 	}
 	var inData interface{}
 	dec := sonic.ConfigDefault.NewDecoder(bytes.NewBuffer(inBytes))
+	dec.UseNumber()
 	err := dec.Decode(&inData)
 	if err != nil || inData == nil {
 		//return errors.Errorf(ErrNotJson, in)
@@ -197,18 +256,45 @@ func (cli *anyClient) stringToAny(in interface{}, outVal reflect.Value) error {
 	inDataType, _ := ReflectTypeValue(inData)
 	switch inDataType.Kind() {
 	case reflect.Map, reflect.Slice:
+		cli.state.jsonNumbers++
+		defer func() { cli.state.jsonNumbers-- }()
 		return cli.decodeAny(inData, outVal)
 	default:
 		return errors.Errorf(ErrNotJson, in) // reject other types
 	}
 }
 
+// jsonNumbersToFloat returns a json value with its json numbers converted to float64. Maps and lists
+// holding numbers are copied, so that the decoded json is not changed for other outputs.
+func jsonNumbersToFloat(v interface{}) interface{} {
+	switch t := v.(type) {
+	case json.Number:
+		if f, err := t.Float64(); err == nil {
+			return f
+		}
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			out[k] = jsonNumbersToFloat(e)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, e := range t {
+			out[i] = jsonNumbersToFloat(e)
+		}
+		return out
+	}
+	return v
+}
+
 // isBasicType returns true if the type is a basic type.
 func isBasicType(v reflect.Kind) bool {
 	switch v {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
 	case reflect.Float32, reflect.Float64:
+	case reflect.Complex64, reflect.Complex128:
 	case reflect.Bool:
 	case reflect.String:
 	default:
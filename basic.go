@@ -1,157 +1,276 @@
 package goany
 
 import (
+	"encoding/json"
 	"math"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 	"unsafe"
 
-	"github.com/bytedance/sonic"
 	"github.com/pkg/errors"
 )
 
 // supported basic type
 // int, int8, int16, int32, int64
-// uint, uint8, uint16, uint32, uint64
+// uint, uint8, uint16, uint32, uint64, uintptr
 // float32, float64
+// complex64, complex128
 // bool
 // string
 
 // ToInt64 convert an interface to an int64 type.
 func ToInt64(v interface{}) int64 {
-	out, _ := toInt64E(v)
+	out, _ := ToInt64E(v)
 	return out
 }
 
 func ToInt32(v interface{}) int32 {
-	out, _ := toInt64E(v)
-	return int32(out)
+	out, _ := ToInt32E(v)
+	return out
 }
 
 func ToInt16(v interface{}) int16 {
-	out, _ := toInt64E(v)
-	return int16(out)
+	out, _ := ToInt16E(v)
+	return out
 }
 
 func ToInt8(v interface{}) int8 {
-	out, _ := toInt64E(v)
-	return int8(out)
+	out, _ := ToInt8E(v)
+	return out
 }
 
 func ToInt(v interface{}) int {
-	out, _ := toInt64E(v)
-	return int(out)
+	out, _ := ToIntE(v)
+	return out
 }
 
 // ToInt64E is a function that attempts to convert an arbitrary type to an int64.
 func ToInt64E(v interface{}, op ...Options) (int64, error) {
-	if len(op) == 0 {
-		op = append(op, *NewOptions())
-	}
-	return toInt64E(v)
+	var out int64
+	err := toScalarE(v, &out, op)
+	return out, err
+}
+
+// ToInt32E converts an interface to an int32, returning an error if the value is out of range.
+func ToInt32E(v interface{}, op ...Options) (int32, error) {
+	var out int32
+	err := toScalarE(v, &out, op)
+	return out, err
+}
+
+// ToInt16E converts an interface to an int16, returning an error if the value is out of range.
+func ToInt16E(v interface{}, op ...Options) (int16, error) {
+	var out int16
+	err := toScalarE(v, &out, op)
+	return out, err
+}
+
+// ToInt8E converts an interface to an int8, returning an error if the value is out of range.
+func ToInt8E(v interface{}, op ...Options) (int8, error) {
+	var out int8
+	err := toScalarE(v, &out, op)
+	return out, err
+}
+
+// ToIntE converts an interface to an int, returning an error if the value is out of range.
+func ToIntE(v interface{}, op ...Options) (int, error) {
+	var out int
+	err := toScalarE(v, &out, op)
+	return out, err
 }
 
 func ToUint64(v interface{}) uint64 {
-	out, _ := toUint64E(v)
+	out, _ := ToUint64E(v)
 	return out
 }
 
 func ToUint32(v interface{}) uint32 {
-	out, _ := toUint64E(v)
-	return uint32(out)
+	out, _ := ToUint32E(v)
+	return out
 }
 
 func ToUint16(v interface{}) uint16 {
-	out, _ := toUint64E(v)
-	return uint16(out)
+	out, _ := ToUint16E(v)
+	return out
 }
 
 func ToUint8(v interface{}) uint8 {
-	out, _ := toUint64E(v)
-	return uint8(out)
+	out, _ := ToUint8E(v)
+	return out
 }
 
 func ToUint(v interface{}) uint {
-	out, _ := toUint64E(v)
-	return uint(out)
+	out, _ := ToUintE(v)
+	return out
 }
 
 func ToUint64E(v interface{}, op ...Options) (uint64, error) {
-	if len(op) == 0 {
-		op = append(op, *NewOptions())
-	}
-	return toUint64E(v)
+	var out uint64
+	err := toScalarE(v, &out, op)
+	return out, err
+}
+
+// ToUint32E converts an interface to an uint32, returning an error if the value is out of range.
+func ToUint32E(v interface{}, op ...Options) (uint32, error) {
+	var out uint32
+	err := toScalarE(v, &out, op)
+	return out, err
+}
+
+// ToUint16E converts an interface to an uint16, returning an error if the value is out of range.
+func ToUint16E(v interface{}, op ...Options) (uint16, error) {
+	var out uint16
+	err := toScalarE(v, &out, op)
+	return out, err
+}
+
+// ToUint8E converts an interface to an uint8, returning an error if the value is out of range.
+func ToUint8E(v interface{}, op ...Options) (uint8, error) {
+	var out uint8
+	err := toScalarE(v, &out, op)
+	return out, err
+}
+
+// ToUintE converts an interface to an uint, returning an error if the value is out of range.
+func ToUintE(v interface{}, op ...Options) (uint, error) {
+	var out uint
+	err := toScalarE(v, &out, op)
+	return out, err
 }
 
 // ToFloat32 convert an interface to a float32 type
 func ToFloat32(v interface{}) float32 {
-	out, _ := toFloat64E(v)
-	return float32(out)
+	out, _ := ToFloat32E(v)
+	return out
 }
 
 // ToFloat64 convert an interface to a float64 type
 func ToFloat64(v interface{}) float64 {
-	out, _ := toFloat64E(v)
+	out, _ := ToFloat64E(v)
 	return out
 }
 
+// ToFloat32E converts an interface to a float32. Values out of the float32 range and,
+// with LossyError, values that can not be represented exactly return an error.
+func ToFloat32E(v interface{}, op ...Options) (float32, error) {
+	var out float32
+	err := toScalarE(v, &out, op)
+	return out, err
+}
+
 func ToFloat64E(v interface{}, op ...Options) (float64, error) {
-	if len(op) == 0 {
-		op = append(op, *NewOptions())
-	}
-	return toFloat64E(v)
+	var out float64
+	err := toScalarE(v, &out, op)
+	return out, err
 }
 
 // ToString convert an interface to a string type
 func ToString(v interface{}) string {
-	out, _ := toStringE(v, *NewOptions())
+	out, _ := ToStringE(v)
 	return out
 }
 
 func ToStringE(v interface{}, op ...Options) (string, error) {
-	if len(op) == 0 {
-		op = append(op, *NewOptions())
-	}
-	return toStringE(v, op[0])
+	var out string
+	err := toScalarE(v, &out, op)
+	return out, err
+}
+
+// ToComplex64 convert an interface to a complex64 type
+func ToComplex64(v interface{}) complex64 {
+	out, _ := ToComplex64E(v)
+	return out
+}
+
+// ToComplex128 convert an interface to a complex128 type
+func ToComplex128(v interface{}) complex128 {
+	out, _ := ToComplex128E(v)
+	return out
+}
+
+func ToComplex64E(v interface{}, op ...Options) (complex64, error) {
+	var out complex64
+	err := toScalarE(v, &out, op)
+	return out, err
+}
+
+func ToComplex128E(v interface{}, op ...Options) (complex128, error) {
+	var out complex128
+	err := toScalarE(v, &out, op)
+	return out, err
 }
 
 func ToBool(v interface{}) bool {
-	out, _ := toBoolE(v)
+	out, _ := ToBoolE(v)
 	return out
 }
 
 // ToBoolE convert an interface to a bool type
 func ToBoolE(v interface{}, op ...Options) (bool, error) {
-	if len(op) == 0 {
-		op = append(op, *NewOptions())
+	var out bool
+	err := toScalarE(v, &out, op)
+	return out, err
+}
+
+// toScalarE converts v into the basic, time.Time or time.Duration value out points to, with a client built from op.
+// Like decodeAny, the hooks run first for non-nil input and may set the value themselves,
+// then the value is decoded with the options of the client.
+func toScalarE(v interface{}, out interface{}, op []Options) error {
+	cli := newAnyClient(op...)
+	outVal := reflect.ValueOf(out).Elem()
+	if !CheckInIsNil(Indirect(v)) {
+		status, err := cli.runHooks(v, outVal)
+		if err != nil || status != DecodeContinue {
+			return err
+		}
 	}
-	return toBoolE(v)
+	switch out.(type) {
+	case *time.Time:
+		return cli.decodeTime(v, outVal)
+	case *time.Duration:
+		return cli.decodeDuration(v, outVal)
+	}
+	return cli.decodeBasic(v, outVal)
 }
 
 // decodeBasic is a function that attempts to convert an arbitrary type to a basic type.
 func (cli *anyClient) decodeBasic(in interface{}, outVal reflect.Value) error {
 	switch outVal.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		result, err := toInt64E(in)
+		if r, ok := stringToChar(in, outVal.Kind(), *cli.options); ok {
+			in = int64(r)
+		}
+		result, err := toIntKindE(in, outVal.Kind(), *cli.options)
 		if err != nil {
 			return err
 		}
 		reflect.NewAt(outVal.Type(), unsafe.Pointer(outVal.UnsafeAddr())).Elem().SetInt(result)
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		result, err := toUint64E(in)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if r, ok := stringToChar(in, outVal.Kind(), *cli.options); ok {
+			in = int64(r)
+		}
+		result, err := toUintKindE(in, outVal.Kind(), *cli.options)
 		if err != nil {
 			return err
 		}
 		reflect.NewAt(outVal.Type(), unsafe.Pointer(outVal.UnsafeAddr())).Elem().SetUint(result)
 	case reflect.Float32, reflect.Float64:
-		result, err := toFloat64E(in)
+		result, err := toFloatKindE(in, outVal.Kind(), *cli.options)
 		if err != nil {
 			return err
 		}
 		reflect.NewAt(outVal.Type(), unsafe.Pointer(outVal.UnsafeAddr())).Elem().SetFloat(result)
+	case reflect.Complex64, reflect.Complex128:
+		result, err := toComplexKindE(in, outVal.Kind(), *cli.options)
+		if err != nil {
+			return err
+		}
+		reflect.NewAt(outVal.Type(), unsafe.Pointer(outVal.UnsafeAddr())).Elem().SetComplex(result)
 	case reflect.Bool:
-		result, err := toBoolE(in)
+		result, err := toBoolE(in, *cli.options)
 		if err != nil {
 			return err
 		}
@@ -176,47 +295,54 @@ func (cli *anyClient) decodeBasic(in interface{}, outVal reflect.Value) error {
 // If the input is nil, it returns 0 and nil.
 //
 // The function handles the following types:
-// - String: It uses strconv.ParseInt to convert the string to an int64.
+// - String: It uses strconv.ParseInt to convert the string to an int64, or the number format of op if one is set.
 // - Int, Int8, Int16, Int32, Int64: It directly returns the int value.
 // - Uint, Uint8, Uint16, Uint32, Uint64: It converts the uint value to an int64 and returns it.
-// - Float32, Float64: It converts the float value to an int64 and returns it.
+// - Float32, Float64: It converts the float value to an int64, the fraction is handled by the lossy policy of op.
 // - Bool: It returns 1 if the bool is true, and 0 if it's false.
-// - Struct: If the struct is of type time.Time, it returns the Unix timestamp. Otherwise, it returns an error.
+// - Struct: If the struct is a time type (see asTime), it returns the Unix timestamp in the time unit of op. Otherwise, it returns an error.
 // - Slice: If the slice is of type []uint8å (a byte slice), it converts the byte slice to a string and then uses strconv.ParseInt to convert the string to an int64. Otherwise, it returns an error.
+// - json.Number: It is parsed as an integer first, so large ids keep their precision, and falls back to a float.
 //
 // If the input is of any other type, the function returns an error.
-func toInt64E(v interface{}) (int64, error) {
+func toInt64E(v interface{}, op Options) (int64, error) {
 	v = Indirect(v)
 	if CheckInIsNil(v) {
 		return 0, nil
 	}
 
+	if n, ok := v.(json.Number); ok {
+		return jsonNumberToInt64(n, op)
+	}
+
 	switch outVal := reflect.ValueOf(v); outVal.Kind() {
 	case reflect.String:
-		if i, err := strconv.Atoi(outVal.String()); err == nil {
-			return int64(i), nil
-		}
-		return strconv.ParseInt(outVal.String(), 10, 64)
+		return stringToInt64(outVal.String(), op)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		return outVal.Int(), nil
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
 		if outVal.Uint() > math.MaxInt64 {
-			return 0, errors.Errorf(ErrUnableConvertInt64, v)
+			if op.lossyPolicy == LossySaturate {
+				return math.MaxInt64, nil
+			}
+			return 0, errors.Errorf(ErrValueOverflow, v, "int64")
 		}
 		return int64(outVal.Uint()), nil
 	case reflect.Float32, reflect.Float64:
-		if f := outVal.Float(); f > math.MaxInt64 {
-			return 0, errors.Errorf(ErrUnableConvertInt64, v)
+		return floatToInt64(outVal.Float(), v, op)
+	case reflect.Complex64, reflect.Complex128:
+		if c := outVal.Complex(); imag(c) == 0 {
+			return floatToInt64(real(c), v, op)
 		}
-		return int64(outVal.Float()), nil
+		return 0, errors.Errorf(ErrUnableConvertInt64, v)
 	case reflect.Bool:
 		if outVal.Bool() {
 			return 1, nil
 		}
 		return 0, nil
 	case reflect.Struct:
-		if t, ok := v.(time.Time); ok {
-			return t.Unix(), nil
+		if t, ok := asTime(v, op.location); ok {
+			return timeToUnix(t, op.timeUnit), nil
 		}
 		return 0, errors.Errorf(ErrUnableConvertInt64, v)
 	case reflect.Slice:
@@ -224,7 +350,7 @@ func toInt64E(v interface{}) (int64, error) {
 			if len(b) == 0 {
 				return 0, nil
 			}
-			return strconv.ParseInt(string(b), 10, 64)
+			return stringToInt64(string(b), op)
 		}
 		return 0, errors.Errorf(ErrUnableConvertInt64, v)
 	default:
@@ -233,32 +359,34 @@ func toInt64E(v interface{}) (int64, error) {
 }
 
 // toUint64E converts an interface to an uint64 type.
-func toUint64E(v interface{}) (uint64, error) {
+func toUint64E(v interface{}, op Options) (uint64, error) {
 	v = Indirect(v)
 	if CheckInIsNil(v) {
 		return 0, nil
 	}
+	if n, ok := v.(json.Number); ok {
+		return jsonNumberToUint64(n, op)
+	}
 	switch outVal := reflect.ValueOf(v); outVal.Kind() {
 	case reflect.String:
-		if i, err := strconv.ParseInt(outVal.String(), 10, 64); err == nil {
-			if i < 0 {
-				return 0, errors.Errorf(ErrUnableConvertInt64, v)
-			}
-			return uint64(i), nil
-		}
-		return strconv.ParseUint(outVal.String(), 10, 64)
+		return stringToUint64(outVal.String(), op)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		if outVal.Int() < 0 {
-			return 0, errors.Errorf(ErrUnableConvertInt64, v)
+			if op.lossyPolicy == LossySaturate {
+				return 0, nil
+			}
+			return 0, errors.Errorf(ErrValueOverflow, v, "uint64")
 		}
 		return uint64(outVal.Int()), nil
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
 		return outVal.Uint(), nil
 	case reflect.Float32, reflect.Float64:
-		if f := outVal.Float(); f < 0 {
-			return 0, errors.Errorf(ErrUnableConvertInt64, v)
+		return floatToUint64(outVal.Float(), v, op)
+	case reflect.Complex64, reflect.Complex128:
+		if c := outVal.Complex(); imag(c) == 0 {
+			return floatToUint64(real(c), v, op)
 		}
-		return uint64(outVal.Float()), nil
+		return 0, errors.Errorf(ErrUnableConvertUint64, v)
 	case reflect.Bool:
 		if outVal.Bool() {
 			return 1, nil
@@ -269,7 +397,7 @@ func toUint64E(v interface{}) (uint64, error) {
 			if len(b) == 0 {
 				return 0, nil
 			}
-			return strconv.ParseUint(string(b), 10, 64)
+			return stringToUint64(string(b), op)
 		}
 		return 0, errors.Errorf(ErrUnableConvertUint64, v)
 	default:
@@ -278,20 +406,33 @@ func toUint64E(v interface{}) (uint64, error) {
 }
 
 // toFloat64E converts an interface to a float64 type.
-func toFloat64E(v interface{}) (float64, error) {
+func toFloat64E(v interface{}, op Options) (float64, error) {
 	v = Indirect(v)
 	if CheckInIsNil(v) {
 		return 0, nil
 	}
+	if n, ok := v.(json.Number); ok {
+		return n.Float64()
+	}
 	switch outVal := reflect.ValueOf(v); outVal.Kind() {
 	case reflect.String:
-		return strconv.ParseFloat(outVal.String(), 64)
+		return stringToFloat64(outVal.String(), op)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		return float64(outVal.Int()), nil
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
 		return float64(outVal.Uint()), nil
 	case reflect.Float32, reflect.Float64:
 		return outVal.Float(), nil
+	case reflect.Complex64, reflect.Complex128:
+		if c := outVal.Complex(); imag(c) == 0 {
+			return real(c), nil
+		}
+		return 0, errors.Errorf(ErrUnableConvertFloat64, v)
+	case reflect.Struct:
+		if t, ok := asTime(v, op.location); ok {
+			return timeToUnixFloat(t, op.timeUnit), nil
+		}
+		return 0, errors.Errorf(ErrUnableConvertFloat64, v)
 	case reflect.Bool:
 		if outVal.Bool() {
 			return 1, nil
@@ -299,7 +440,7 @@ func toFloat64E(v interface{}) (float64, error) {
 		return 0, nil
 	case reflect.Slice:
 		if byteSlice, ok := v.([]uint8); ok {
-			return strconv.ParseFloat(string(byteSlice), 64)
+			return stringToFloat64(string(byteSlice), op)
 		}
 		return 0, errors.Errorf(ErrUnableConvertFloat64, v)
 	default:
@@ -307,12 +448,48 @@ func toFloat64E(v interface{}) (float64, error) {
 	}
 }
 
+// toComplex128E converts an interface to a complex128 type. Strings are parsed by
+// strconv.ParseComplex, real numbers become the real part.
+func toComplex128E(v interface{}, op Options) (complex128, error) {
+	v = Indirect(v)
+	if CheckInIsNil(v) {
+		return 0, nil
+	}
+	outVal := reflect.ValueOf(v)
+	switch outVal.Kind() {
+	case reflect.Complex64, reflect.Complex128:
+		return outVal.Complex(), nil
+	case reflect.String:
+		if _, ok := v.(json.Number); !ok {
+			return strconv.ParseComplex(strings.TrimSpace(outVal.String()), 128)
+		}
+	case reflect.Slice:
+		if byteSlice, ok := v.([]uint8); ok {
+			return strconv.ParseComplex(strings.TrimSpace(string(byteSlice)), 128)
+		}
+	}
+	f, err := toFloat64E(v, op)
+	if err != nil {
+		return 0, errors.Errorf(ErrUnableConvertComplex, v)
+	}
+	return complex(f, 0), nil
+}
+
 // toStringE converts an interface to a string type.
 func toStringE(v interface{}, op Options) (string, error) {
 	v = Indirect(v)
 	if CheckInIsNil(v) {
 		return "", nil
 	}
+	if n, ok := v.(json.Number); ok {
+		return n.String(), nil
+	}
+	if s, ok := charToString(v, op); ok {
+		return s, nil
+	}
+	if d, ok := v.(time.Duration); ok {
+		return formatDuration(d, op), nil
+	}
 	switch outVal := reflect.ValueOf(v); outVal.Kind() {
 	case reflect.String:
 		if _, ok := v.(string); ok {
@@ -321,55 +498,174 @@ func toStringE(v interface{}, op Options) (string, error) {
 		return outVal.String(), nil
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		return strconv.FormatInt(outVal.Int(), 10), nil
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
 		return strconv.FormatUint(outVal.Uint(), 10), nil
 	case reflect.Float32, reflect.Float64:
 		return strconv.FormatFloat(outVal.Float(), 'f', -1, 64), nil
+	case reflect.Complex64, reflect.Complex128:
+		return strconv.FormatComplex(outVal.Complex(), 'f', -1, 128), nil
 	case reflect.Bool:
 		return strconv.FormatBool(v.(bool)), nil
 	case reflect.Struct:
-		if t, ok := v.(time.Time); ok {
-			return t.Format(op.timeFormat), nil
+		switch c := v.(type) {
+		case Date:
+			return c.String(), nil
+		case TimeOfDay:
+			return c.String(), nil
+		case TimeRange:
+			return formatTime(c.Start, op) + "/" + formatTime(c.End, op), nil
 		}
-		b, err := sonic.Marshal(v)
+		if t, ok := asTime(v, op.location); ok {
+			return formatTime(t, op), nil
+		}
+		b, err := marshalJSON(v, op)
 		return string(b), err
 	case reflect.Map:
-		b, err := sonic.Marshal(v)
+		b, err := marshalJSON(v, op)
 		return string(b), err
 	case reflect.Slice, reflect.Array:
 		if b, ok := v.([]byte); ok {
 			return string(b), nil
 		}
-		b, err := sonic.Marshal(v)
+		b, err := marshalJSON(v, op)
 		return string(b), err
 	default:
 		return "", errors.Errorf(ErrUnableConvertString, v)
 	}
 }
 
-// toBoolE converts an interface to a bool type.
-func toBoolE(v interface{}) (bool, error) {
+// toBoolE converts an interface to a bool type. Strings are looked up in the boolean
+// vocabulary of op, numbers are true if not zero, or must be 0 or 1 in strict mode.
+func toBoolE(v interface{}, op Options) (bool, error) {
 	v = Indirect(v)
 	if CheckInIsNil(v) {
 		return false, nil
 	}
+	if n, ok := v.(json.Number); ok {
+		f, err := n.Float64()
+		if err != nil {
+			return false, errors.Errorf(ErrUnableConvertBool, v)
+		}
+		return numberToBool(f, v, op)
+	}
 	switch outVal := reflect.ValueOf(v); outVal.Kind() {
 	case reflect.String:
-		return strconv.ParseBool(outVal.String())
+		return stringToBool(outVal.String(), op)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return outVal.Int() != 0, nil
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return outVal.Uint() != 0, nil
+		return numberToBool(float64(outVal.Int()), v, op)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return numberToBool(float64(outVal.Uint()), v, op)
 	case reflect.Float32, reflect.Float64:
-		return outVal.Float() != 0, nil
+		return numberToBool(outVal.Float(), v, op)
+	case reflect.Complex64, reflect.Complex128:
+		if c := outVal.Complex(); imag(c) == 0 {
+			return numberToBool(real(c), v, op)
+		} else if op.boolStrict {
+			return false, errors.Errorf(ErrUnableConvertBool, v)
+		}
+		return true, nil
 	case reflect.Bool:
 		return outVal.Bool(), nil
 	case reflect.Slice:
 		if byteSlice, ok := v.([]uint8); ok {
-			return strconv.ParseBool(string(byteSlice))
+			return stringToBool(string(byteSlice), op)
 		}
 		return false, errors.Errorf(ErrUnableConvertFloat64, v)
 	default:
 		return false, errors.Errorf(ErrUnableConvertBool, v)
 	}
 }
+
+// stringToBool looks up a string in the boolean vocabulary of op, ignoring case and surrounding spaces.
+func stringToBool(s string, op Options) (bool, error) {
+	values := op.boolValues
+	if values == nil {
+		values = defaultBoolValues
+	}
+	if b, ok := values[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return b, nil
+	}
+	return false, errors.Errorf(ErrUnableConvertBool, s)
+}
+
+// numberToBool returns true if f is not zero. In strict mode only 0 and 1 are accepted.
+func numberToBool(f float64, in interface{}, op Options) (bool, error) {
+	if op.boolStrict && f != 0 && f != 1 {
+		return false, errors.Errorf(ErrUnableConvertBool, in)
+	}
+	return f != 0, nil
+}
+
+// jsonNumberToInt64 converts a json.Number to an int64. Integer literals are parsed
+// directly so values above 2^53 are not rounded through float64, other literals such
+// as "1.5" or "1e3" are parsed as floats and converted like any float input.
+func jsonNumberToInt64(n json.Number, op Options) (int64, error) {
+	if i, err := strconv.ParseInt(n.String(), 10, 64); err == nil {
+		return i, nil
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return 0, errors.Errorf(ErrUnableConvertInt64, n)
+	}
+	return floatToInt64(f, n, op)
+}
+
+// jsonNumberToUint64 converts a json.Number to an uint64, see jsonNumberToInt64.
+func jsonNumberToUint64(n json.Number, op Options) (uint64, error) {
+	if i, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+		return i, nil
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return 0, errors.Errorf(ErrUnableConvertUint64, n)
+	}
+	return floatToUint64(f, n, op)
+}
+
+// stringToChar returns the character of a single character string when the rune/byte
+// semantics are enabled, the kind is int32 (rune) or uint8 (byte) and the input is a string
+// holding exactly one rune or one byte. Other input is left to the numeric converters.
+func stringToChar(in interface{}, kind reflect.Kind, op Options) (rune, bool) {
+	if !op.runeAsChar {
+		return 0, false
+	}
+	s, ok := Indirect(in).(string)
+	if !ok {
+		return 0, false
+	}
+	switch kind {
+	case reflect.Int32:
+		if r, size := utf8.DecodeRuneInString(s); size > 0 && size == len(s) && r != utf8.RuneError {
+			return r, true
+		}
+	case reflect.Uint8:
+		if len(s) == 1 {
+			return rune(s[0]), true
+		}
+	}
+	return 0, false
+}
+
+// charToString converts a rune, a byte or a rune slice to the text it holds when the
+// rune/byte semantics are enabled, so 'a' becomes "a" instead of "97".
+func charToString(v interface{}, op Options) (string, bool) {
+	if !op.runeAsChar {
+		return "", false
+	}
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.Int32:
+		return string(rune(val.Int())), true
+	case reflect.Uint8:
+		return string([]byte{byte(val.Uint())}), true
+	case reflect.Slice:
+		if val.Type().Elem().Kind() == reflect.Int32 {
+			runes := make([]rune, val.Len())
+			for i := range runes {
+				runes[i] = rune(val.Index(i).Int())
+			}
+			return string(runes), true
+		}
+	}
+	return "", false
+}
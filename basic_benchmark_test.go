@@ -32,7 +32,7 @@ func BenchmarkToInt64E(b *testing.B) {
 	for _, bm := range benchmarks {
 		b.Run(bm.name, func(b *testing.B) {
 			for i := 0; i < b.N; i++ {
-				_, _ = toInt64E(bm.input)
+				_, _ = toInt64E(bm.input, *NewOptions())
 			}
 		})
 	}
@@ -65,7 +65,7 @@ func BenchmarkToUint64E(b *testing.B) {
 	for _, bm := range benchmarks {
 		b.Run(bm.name, func(b *testing.B) {
 			for i := 0; i < b.N; i++ {
-				_, _ = toUint64E(bm.input)
+				_, _ = toUint64E(bm.input, *NewOptions())
 			}
 		})
 	}
@@ -86,7 +86,7 @@ func BenchmarkToFloat64E(b *testing.B) {
 	for _, tc := range testCases {
 		b.Run(tc.name, func(b *testing.B) {
 			for i := 0; i < b.N; i++ {
-				_, _ = toFloat64E(tc.input)
+				_, _ = toFloat64E(tc.input, *NewOptions())
 			}
 		})
 	}
@@ -152,7 +152,7 @@ func BenchmarkToBoolE(b *testing.B) {
 	for _, bm := range benchmarks {
 		b.Run(bm.name, func(b *testing.B) {
 			for i := 0; i < b.N; i++ {
-				_, _ = toBoolE(bm.input)
+				_, _ = toBoolE(bm.input, *NewOptions())
 			}
 		})
 	}
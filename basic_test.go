@@ -1,9 +1,12 @@
 package goany
 
 import (
+	"encoding/json"
 	"fmt"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"math"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -100,6 +103,19 @@ func TestToInt64E(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, now.Unix(), v)
 	})
+
+	t.Run("Test with json.Number", func(t *testing.T) {
+		v, err := ToInt64E(json.Number("9007199254740993"))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(9007199254740993), v)
+
+		v, err = ToInt64E(json.Number("12.5"))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(12), v)
+
+		_, err = ToInt64E(json.Number("1e30"))
+		assert.Error(t, err)
+	})
 }
 
 func TestToUint64E(t *testing.T) {
@@ -181,6 +197,15 @@ func TestToUint64E(t *testing.T) {
 		_, err := ToUint64E([]NByte("123"))
 		assert.Error(t, err)
 	})
+
+	t.Run("Test with json.Number", func(t *testing.T) {
+		v, err := ToUint64E(json.Number("18446744073709551615"))
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(math.MaxUint64), v)
+
+		_, err = ToUint64E(json.Number("-1"))
+		assert.Error(t, err)
+	})
 }
 
 func TestToFloat64E(t *testing.T) {
@@ -251,6 +276,12 @@ func TestToFloat64E(t *testing.T) {
 		_, err := ToFloat64E([]NByte("123"))
 		assert.Error(t, err)
 	})
+
+	t.Run("Test with json.Number", func(t *testing.T) {
+		v, err := ToFloat64E(json.Number("1.25"))
+		assert.NoError(t, err)
+		assert.Equal(t, 1.25, v)
+	})
 }
 
 func TestToStringE(t *testing.T) {
@@ -307,6 +338,12 @@ func TestToStringE(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, "[1,2,3]", v)
 	})
+
+	t.Run("Test with json.Number", func(t *testing.T) {
+		v, err := ToStringE(json.Number("9007199254740993"))
+		assert.NoError(t, err)
+		assert.Equal(t, "9007199254740993", v)
+	})
 }
 
 func TestToBoolE(t *testing.T) {
@@ -366,6 +403,82 @@ func TestToBoolE(t *testing.T) {
 		_, err = ToBoolE(time.Now())
 		assert.Error(t, err)
 	})
+
+	t.Run("Test with json.Number", func(t *testing.T) {
+		v, err := ToBoolE(json.Number("2"))
+		assert.NoError(t, err)
+		assert.Equal(t, true, v)
+	})
+
+	t.Run("Test with default vocabulary", func(t *testing.T) {
+		for _, in := range []string{"yes", "Y", "ON", "Enabled", " true ", "T", "1"} {
+			v, err := ToBoolE(in)
+			assert.NoError(t, err, in)
+			assert.Equal(t, true, v, in)
+		}
+		for _, in := range []string{"no", "N", "off", "DISABLED", "", "False", "0"} {
+			v, err := ToBoolE(in)
+			assert.NoError(t, err, in)
+			assert.Equal(t, false, v, in)
+		}
+		_, err := ToBoolE("maybe")
+		assert.Error(t, err)
+	})
+
+	t.Run("Test with custom vocabulary", func(t *testing.T) {
+		op := NewOptions().AddBoolValue("Ja", true).AddBoolValue("nein", false)
+		v, err := ToBoolE("JA", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, true, v)
+
+		v, err = ToBoolE("yes", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, true, v)
+
+		op = NewOptions().SetBoolValues(map[string]bool{"Active": true, "inactive": false})
+		v, err = ToBoolE("active", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, true, v)
+
+		_, err = ToBoolE("yes", *op)
+		assert.Error(t, err)
+
+		v, err = ToBoolE("yes")
+		assert.NoError(t, err)
+		assert.Equal(t, true, v)
+	})
+
+	t.Run("Test with strict", func(t *testing.T) {
+		op := NewOptions().SetBoolStrict(true)
+		_, err := ToBoolE(2, *op)
+		assert.Error(t, err)
+
+		_, err = ToBoolE(json.Number("0.5"), *op)
+		assert.Error(t, err)
+
+		v, err := ToBoolE(uint8(1), *op)
+		assert.NoError(t, err)
+		assert.Equal(t, true, v)
+	})
+
+	t.Run("Test with struct field", func(t *testing.T) {
+		type feature struct {
+			Name    string `json:"name"`
+			Enabled bool   `json:"enabled"`
+		}
+		type config struct {
+			Debug    bool      `json:"debug"`
+			Features []feature `json:"features"`
+		}
+		var out config
+		in := map[string]interface{}{
+			"debug":    "on",
+			"features": []interface{}{map[string]interface{}{"name": "a", "enabled": "yes"}, map[string]interface{}{"name": "b", "enabled": ""}},
+		}
+		err := ToAny(in, &out)
+		assert.NoError(t, err)
+		assert.Equal(t, config{Debug: true, Features: []feature{{Name: "a", Enabled: true}, {Name: "b"}}}, out)
+	})
 }
 
 func TestBasicOther(t *testing.T) {
@@ -442,3 +555,197 @@ func TestBasicOther(t *testing.T) {
 		assert.Equal(t, "1", vs)
 	})
 }
+
+func TestBasicOptions(t *testing.T) {
+	t.Run("Test with hook set value", func(t *testing.T) {
+		hook := func(in interface{}, out reflect.Value) (int, error) {
+			if s, ok := in.(string); ok && s == "max" && out.Kind() == reflect.Int64 {
+				out.SetInt(math.MaxInt64)
+				return DecodeSkip, nil
+			}
+			return DecodeContinue, nil
+		}
+		op := NewOptions().AddHook(hook)
+		v, err := ToInt64E("max", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(math.MaxInt64), v)
+
+		v, err = ToInt64E("12", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(12), v)
+	})
+
+	t.Run("Test with hook error", func(t *testing.T) {
+		hookErr := errors.New("hook error")
+		op := NewOptions().AddHook(func(in interface{}, out reflect.Value) (int, error) {
+			return DecodeContinue, hookErr
+		})
+		_, err := ToBoolE("true", *op)
+		assert.Equal(t, hookErr, err)
+		_, err = ToFloat32E(1, *op)
+		assert.Equal(t, hookErr, err)
+		_, err = ToTimeE("2024-01-01", *op)
+		assert.Equal(t, hookErr, err)
+	})
+
+	t.Run("Test with hook not called for nil", func(t *testing.T) {
+		op := NewOptions().AddHook(func(in interface{}, out reflect.Value) (int, error) {
+			return DecodeStop, errors.New("hook called")
+		})
+		v, err := ToUint8E(nil, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, uint8(0), v)
+	})
+
+	t.Run("Test with every width honor options", func(t *testing.T) {
+		op := NewOptions().SetNumberFormat(NumberRadix).SetLossyPolicy(LossySaturate)
+		v8, err := ToInt8E("0x7fff", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, int8(math.MaxInt8), v8)
+
+		vu16, err := ToUint16E("0x10", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, uint16(16), vu16)
+
+		vf, err := ToFloat32E("0x10", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, float32(16), vf)
+
+		vb, err := ToBoolE("ja", *NewOptions().AddBoolValue("ja", true))
+		assert.NoError(t, err)
+		assert.Equal(t, true, vb)
+	})
+}
+
+func TestComplexAndUintptr(t *testing.T) {
+	t.Run("Test with complex", func(t *testing.T) {
+		v, err := ToComplex128E("1+2i")
+		assert.NoError(t, err)
+		assert.Equal(t, complex(1, 2), v)
+
+		v, err = ToComplex128E(json.Number("1.5"))
+		assert.NoError(t, err)
+		assert.Equal(t, complex(1.5, 0), v)
+
+		v64, err := ToComplex64E(3)
+		assert.NoError(t, err)
+		assert.Equal(t, complex64(complex(3, 0)), v64)
+
+		_, err = ToComplex64E(complex(1e300, 0))
+		assert.Error(t, err)
+
+		_, err = ToComplex128E("abc")
+		assert.Error(t, err)
+	})
+
+	t.Run("Test complex to other", func(t *testing.T) {
+		vi, err := ToInt64E(complex(3, 0))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), vi)
+
+		_, err = ToInt64E(complex(3, 1))
+		assert.Error(t, err)
+
+		vf, err := ToFloat64E(complex64(complex(1.5, 0)))
+		assert.NoError(t, err)
+		assert.Equal(t, 1.5, vf)
+
+		vs, err := ToStringE(complex(1, -2))
+		assert.NoError(t, err)
+		assert.Equal(t, "(1-2i)", vs)
+
+		vb, err := ToBoolE(complex(0, 1))
+		assert.NoError(t, err)
+		assert.Equal(t, true, vb)
+	})
+
+	t.Run("Test with uintptr", func(t *testing.T) {
+		vi, err := ToInt64E(uintptr(12))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(12), vi)
+
+		vs, err := ToStringE(uintptr(12))
+		assert.NoError(t, err)
+		assert.Equal(t, "12", vs)
+	})
+
+	t.Run("Test with struct field", func(t *testing.T) {
+		type signal struct {
+			Value complex128 `json:"value"`
+			Small complex64  `json:"small"`
+			Addr  uintptr    `json:"addr"`
+		}
+		var out signal
+		err := ToAny(map[string]interface{}{"value": "(1+2i)", "small": 2.5, "addr": "4096"}, &out)
+		assert.NoError(t, err)
+		assert.Equal(t, signal{Value: complex(1, 2), Small: complex(2.5, 0), Addr: 4096}, out)
+
+		m := map[string]interface{}{}
+		err = ToAny(out, &m)
+		assert.NoError(t, err)
+		assert.Equal(t, complex(1, 2), m["value"])
+	})
+}
+
+func TestRuneAsChar(t *testing.T) {
+	op := NewOptions().SetRuneAsChar(true)
+
+	t.Run("Test rune and byte to string", func(t *testing.T) {
+		v, err := ToStringE('a', *op)
+		assert.NoError(t, err)
+		assert.Equal(t, "a", v)
+
+		v, err = ToStringE(byte('b'), *op)
+		assert.NoError(t, err)
+		assert.Equal(t, "b", v)
+
+		v, err = ToStringE([]rune("你好"), *op)
+		assert.NoError(t, err)
+		assert.Equal(t, "你好", v)
+
+		v, err = ToStringE('a')
+		assert.NoError(t, err)
+		assert.Equal(t, "97", v)
+	})
+
+	t.Run("Test string to rune and byte", func(t *testing.T) {
+		v, err := ToInt32E("你", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, '你', v)
+
+		v, err = ToInt32E("97", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(97), v)
+
+		vb, err := ToUint8E("7", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, byte('7'), vb)
+
+		_, err = ToUint8E("你", *op)
+		assert.Error(t, err)
+	})
+
+	t.Run("Test string to rune and byte slice", func(t *testing.T) {
+		var runes []rune
+		err := ToAny("héllo", &runes, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, []rune("héllo"), runes)
+
+		var bytes []byte
+		err = ToAny("hé", &bytes, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("hé"), bytes)
+	})
+
+	t.Run("Test with struct field", func(t *testing.T) {
+		type token struct {
+			Sep   rune   `json:"sep"`
+			Quote byte   `json:"quote"`
+			Text  string `json:"text"`
+		}
+		var out token
+		err := ToAny(map[string]interface{}{"sep": ",", "quote": "'", "text": 'x'}, &out, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, token{Sep: ',', Quote: '\'', Text: "x"}, out)
+	})
+}
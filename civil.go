@@ -0,0 +1,235 @@
+package goany
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var timeReflectType = reflect.TypeOf(time.Time{})
+
+// Date is a civil date without time of day and location, such as a birthday.
+// It converts from "2006-01-02" strings, time.Time values and maps with the keys year, month and day.
+type Date struct {
+	Year  int        `json:"year"`
+	Month time.Month `json:"month"`
+	Day   int        `json:"day"`
+}
+
+// TimeOfDay is a civil time of day without date and location, such as an opening hour.
+// It converts from "15:04:05" strings, time.Time values and maps with the keys hour, minute, second and nanosecond.
+type TimeOfDay struct {
+	Hour       int `json:"hour"`
+	Minute     int `json:"minute"`
+	Second     int `json:"second"`
+	Nanosecond int `json:"nanosecond"`
+}
+
+// DateOf returns the date of t in its location.
+func DateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{Year: y, Month: m, Day: d}
+}
+
+// ParseDate parses a "2006-01-02" string.
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return Date{}, errors.Errorf(ErrInToOut, s, "date")
+	}
+	return DateOf(t), nil
+}
+
+// String returns the date in "2006-01-02" format.
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// IsZero reports whether d is the zero value.
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// In returns the midnight that starts d in location.
+func (d Date) In(location *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, location)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Date) UnmarshalText(b []byte) error {
+	v, err := ParseDate(string(b))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// TimeOfDayOf returns the time of day of t in its location.
+func TimeOfDayOf(t time.Time) TimeOfDay {
+	return TimeOfDay{Hour: t.Hour(), Minute: t.Minute(), Second: t.Second(), Nanosecond: t.Nanosecond()}
+}
+
+// ParseTimeOfDay parses a "15:04", "15:04:05" or "15:04:05.999999999" string.
+func ParseTimeOfDay(s string) (TimeOfDay, error) {
+	layout := "15:04:05.999999999"
+	if strings.Count(s, ":") == 1 {
+		layout = "15:04"
+	}
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return TimeOfDay{}, errors.Errorf(ErrInToOut, s, "time of day")
+	}
+	return TimeOfDayOf(t), nil
+}
+
+// String returns the time of day in "15:04:05" format, with the fraction of second if any.
+func (t TimeOfDay) String() string {
+	s := fmt.Sprintf("%02d:%02d:%02d", t.Hour, t.Minute, t.Second)
+	if t.Nanosecond != 0 {
+		s += strings.TrimRight(fmt.Sprintf(".%09d", t.Nanosecond), "0")
+	}
+	return s
+}
+
+// IsZero reports whether t is the zero value, that is midnight.
+func (t TimeOfDay) IsZero() bool {
+	return t == TimeOfDay{}
+}
+
+// On returns the time of day on date d in location.
+func (t TimeOfDay) On(d Date, location *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, t.Hour, t.Minute, t.Second, t.Nanosecond, location)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (t TimeOfDay) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (t *TimeOfDay) UnmarshalText(b []byte) error {
+	v, err := ParseTimeOfDay(string(b))
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// decodeDate decodes an input value into a Date output value. Maps and json objects are decoded field by field,
+// other input is parsed as a date string or converted to time.Time first.
+func (cli *anyClient) decodeDate(in interface{}, outVal reflect.Value) error {
+	if isObjectInput(in) {
+		return cli.decodeStruct(in, outVal)
+	}
+	d, err := toDateE(in, *cli.options)
+	if err != nil {
+		return err
+	}
+	outVal.Set(reflect.ValueOf(d))
+	return nil
+}
+
+// decodeTimeOfDay decodes an input value into a TimeOfDay output value, see decodeDate.
+func (cli *anyClient) decodeTimeOfDay(in interface{}, outVal reflect.Value) error {
+	if isObjectInput(in) {
+		return cli.decodeStruct(in, outVal)
+	}
+	t, err := toTimeOfDayE(in, *cli.options)
+	if err != nil {
+		return err
+	}
+	outVal.Set(reflect.ValueOf(t))
+	return nil
+}
+
+// isObjectInput reports whether in is a map or a json object string, which civil types decode field by field.
+func isObjectInput(in interface{}) bool {
+	inVal := reflect.Indirect(reflect.ValueOf(in))
+	if inVal.Kind() == reflect.String {
+		return strings.HasPrefix(strings.TrimSpace(inVal.String()), "{")
+	}
+	return inVal.Kind() == reflect.Map
+}
+
+// decodeNamedTime decodes an input value into a named type whose underlying type is time.Time,
+// such as `type Date time.Time`.
+func (cli *anyClient) decodeNamedTime(in interface{}, outVal reflect.Value) error {
+	t, err := toTimeE(in, *cli.options)
+	if err != nil {
+		return err
+	}
+	outVal.Set(reflect.ValueOf(t).Convert(outVal.Type()))
+	return nil
+}
+
+// toDateE converts an interface value to a Date.
+func toDateE(in interface{}, op Options) (Date, error) {
+	in = Indirect(in)
+	if CheckInIsNil(in) {
+		return Date{}, nil
+	}
+	if d, ok := in.(Date); ok {
+		return d, nil
+	}
+	if s, ok := in.(string); ok {
+		if d, err := ParseDate(strings.TrimSpace(s)); err == nil {
+			return d, nil
+		}
+	}
+	t, err := toTimeE(in, op)
+	if err != nil {
+		return Date{}, errors.Errorf(ErrInToOut, in, "date")
+	}
+	return DateOf(t), nil
+}
+
+// toTimeOfDayE converts an interface value to a TimeOfDay.
+func toTimeOfDayE(in interface{}, op Options) (TimeOfDay, error) {
+	in = Indirect(in)
+	if CheckInIsNil(in) {
+		return TimeOfDay{}, nil
+	}
+	if t, ok := in.(TimeOfDay); ok {
+		return t, nil
+	}
+	if s, ok := in.(string); ok {
+		if t, err := ParseTimeOfDay(strings.TrimSpace(s)); err == nil {
+			return t, nil
+		}
+	}
+	t, err := toTimeE(in, op)
+	if err != nil {
+		return TimeOfDay{}, errors.Errorf(ErrInToOut, in, "time of day")
+	}
+	return TimeOfDayOf(t), nil
+}
+
+// asTime returns v as a time.Time if it is a time.Time, a named type whose underlying type is
+// time.Time, a Date (midnight in location) or a TimeOfDay (on January 1 of year 0 in location).
+func asTime(v interface{}, location *time.Location) (time.Time, bool) {
+	if location == nil {
+		location = time.UTC
+	}
+	switch t := v.(type) {
+	case time.Time:
+		return t, true
+	case Date:
+		return t.In(location), true
+	case TimeOfDay:
+		return t.On(Date{Month: time.January, Day: 1}, location), true
+	}
+	if val := reflect.ValueOf(v); val.Kind() == reflect.Struct && val.Type().ConvertibleTo(timeReflectType) {
+		return val.Convert(timeReflectType).Interface().(time.Time), true
+	}
+	return time.Time{}, false
+}
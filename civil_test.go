@@ -0,0 +1,141 @@
+package goany
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type namedTime time.Time
+
+func TestNamedTime(t *testing.T) {
+	expected := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("Test with string to named time", func(t *testing.T) {
+		var out namedTime
+		err := ToAny("2024-01-02 03:04:05", &out)
+		assert.NoError(t, err)
+		assert.True(t, expected.Equal(time.Time(out)))
+	})
+
+	t.Run("Test with named time to time and string", func(t *testing.T) {
+		in := namedTime(expected)
+		v, err := ToTimeE(in)
+		assert.NoError(t, err)
+		assert.True(t, expected.Equal(v))
+
+		s, err := ToStringE(in, *NewOptions().SetTimeFormat(time.RFC3339))
+		assert.NoError(t, err)
+		assert.Equal(t, "2024-01-02T03:04:05Z", s)
+
+		assert.Equal(t, expected.Unix(), ToInt64(&in))
+	})
+
+	t.Run("Test with pointer field", func(t *testing.T) {
+		type event struct {
+			At  *namedTime `json:"at"`
+			End *time.Time `json:"end"`
+		}
+		var out event
+		err := ToAny(map[string]interface{}{"at": "2024-01-02 03:04:05", "end": expected.Unix()}, &out)
+		assert.NoError(t, err)
+		assert.True(t, expected.Equal(time.Time(*out.At)))
+		assert.True(t, expected.Equal(*out.End))
+	})
+}
+
+func TestDate(t *testing.T) {
+	expected := Date{Year: 2024, Month: time.February, Day: 29}
+
+	tests := []struct {
+		name  string
+		input interface{}
+	}{
+		{name: "string", input: "2024-02-29"},
+		{name: "datetime string", input: "2024-02-29 23:10:00"},
+		{name: "time", input: time.Date(2024, 2, 29, 8, 0, 0, 0, time.UTC)},
+		{name: "map", input: map[string]interface{}{"year": 2024, "month": 2, "day": 29}},
+		{name: "json", input: `{"year": 2024, "month": 2, "day": 29}`},
+		{name: "date", input: &expected},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out Date
+			err := ToAny(tt.input, &out)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, out)
+		})
+	}
+
+	t.Run("Test with invalid", func(t *testing.T) {
+		var out Date
+		assert.Error(t, ToAny("2024-02-30x", &out))
+		_, err := ParseDate("2023-02-29")
+		assert.Error(t, err)
+	})
+
+	t.Run("Test with date to string, time and map", func(t *testing.T) {
+		assert.Equal(t, "2024-02-29", ToString(expected))
+		assert.Equal(t, "0987-01-05", Date{Year: 987, Month: 1, Day: 5}.String())
+
+		shanghai, _ := time.LoadLocation("Asia/Shanghai")
+		v, err := ToTimeE(expected, *NewOptions().SetLocation(shanghai))
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, shanghai), v)
+
+		var m map[string]interface{}
+		err = ToAny(expected, &m)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"year": 2024, "month": time.February, "day": 29}, m)
+	})
+
+	t.Run("Test with struct field", func(t *testing.T) {
+		type person struct {
+			Birthday Date       `json:"birthday"`
+			Opens    TimeOfDay  `json:"opens"`
+			Closes   *TimeOfDay `json:"closes"`
+		}
+		var out person
+		err := ToAny(`{"birthday": "1990-05-17", "opens": "09:30", "closes": "18:00:00"}`, &out)
+		assert.NoError(t, err)
+		assert.Equal(t, Date{Year: 1990, Month: time.May, Day: 17}, out.Birthday)
+		assert.Equal(t, TimeOfDay{Hour: 9, Minute: 30}, out.Opens)
+		assert.Equal(t, TimeOfDay{Hour: 18}, *out.Closes)
+	})
+}
+
+func TestTimeOfDay(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    interface{}
+		expected TimeOfDay
+	}{
+		{name: "hour and minute", input: "09:30", expected: TimeOfDay{Hour: 9, Minute: 30}},
+		{name: "seconds", input: "23:59:59", expected: TimeOfDay{Hour: 23, Minute: 59, Second: 59}},
+		{name: "fraction", input: "12:00:00.25", expected: TimeOfDay{Hour: 12, Nanosecond: 250000000}},
+		{name: "time", input: time.Date(2024, 1, 1, 7, 8, 9, 10, time.UTC), expected: TimeOfDay{7, 8, 9, 10}},
+		{name: "map", input: map[string]int{"hour": 7, "minute": 15}, expected: TimeOfDay{Hour: 7, Minute: 15}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out TimeOfDay
+			err := ToAny(tt.input, &out)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, out)
+		})
+	}
+
+	t.Run("Test with invalid", func(t *testing.T) {
+		_, err := ParseTimeOfDay("25:00")
+		assert.Error(t, err)
+	})
+
+	t.Run("Test with time of day to string", func(t *testing.T) {
+		assert.Equal(t, "12:00:00.25", ToString(TimeOfDay{Hour: 12, Nanosecond: 250000000}))
+		assert.Equal(t, "07:08:09", TimeOfDay{7, 8, 9, 0}.String())
+
+		d := Date{Year: 2024, Month: 3, Day: 1}
+		assert.Equal(t, time.Date(2024, 3, 1, 7, 8, 9, 0, time.UTC), TimeOfDay{7, 8, 9, 0}.On(d, time.UTC))
+	})
+}
@@ -0,0 +1,227 @@
+package goany
+
+import (
+	"reflect"
+	"time"
+	"unsafe"
+
+	"github.com/pkg/errors"
+)
+
+var locationType = reflect.TypeOf((*time.Location)(nil))
+
+// Clone returns a deep copy of v, see CloneE.
+func Clone[T any](v T, op ...Options) T {
+	out, _ := CloneE(v, op...)
+	return out
+}
+
+// CloneE returns a deep copy of v. Structs, maps, slices, arrays, pointers and interfaces are copied
+// recursively, so the copy shares no memory with v that can be changed. Channels and functions are shared.
+// Unexported fields are shared unless SetExportedUnExported is set, and shared or cyclic pointers need
+// SetCopyShared, otherwise a cycle is an error.
+func CloneE[T any](v T, op ...Options) (T, error) {
+	var out T
+	cli := newAnyClient(op...)
+	copied, err := cli.deepCopy(reflect.ValueOf(&v).Elem())
+	if err != nil {
+		return out, err
+	}
+	reflect.ValueOf(&out).Elem().Set(copied)
+	return out, nil
+}
+
+// DeepCopy copies src into the value dst points to without sharing memory, see CloneE.
+// If src is of another type, it is converted with ToAny and the deep copy option.
+func DeepCopy(dst, src interface{}, op ...Options) error {
+	dstVal := reflect.ValueOf(dst)
+	if dstVal.Kind() != reflect.Ptr || dstVal.IsNil() {
+		return errors.Errorf(ErrUnSupportType, dst)
+	}
+	cli := newAnyClient(op...)
+	srcVal := reflect.ValueOf(src)
+	if srcVal.IsValid() && srcVal.Type() != dstVal.Type().Elem() && srcVal.Kind() == reflect.Ptr && !srcVal.IsNil() {
+		srcVal = srcVal.Elem()
+	}
+	if !srcVal.IsValid() || srcVal.Type() != dstVal.Type().Elem() {
+		options := *cli.options
+		options.deepCopy = true
+		return ToAny(src, dst, options)
+	}
+	copied, err := cli.deepCopy(srcVal)
+	if err != nil {
+		return err
+	}
+	dstVal.Elem().Set(copied)
+	return nil
+}
+
+// copyRef identifies a pointer, map or slice by its type, address and length.
+type copyRef struct {
+	typ reflect.Type
+	ptr uintptr
+	len int
+}
+
+// refOf returns the reference of a non nil pointer, map or slice value.
+func refOf(val reflect.Value) (copyRef, bool) {
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Map:
+		if val.IsNil() {
+			return copyRef{}, false
+		}
+		return copyRef{typ: val.Type(), ptr: val.Pointer()}, true
+	case reflect.Slice:
+		if val.IsNil() {
+			return copyRef{}, false
+		}
+		return copyRef{typ: val.Type(), ptr: val.Pointer(), len: val.Len()}, true
+	default:
+		return copyRef{}, false
+	}
+}
+
+// copier copies values deeply, it remembers the copies of references to copy shared references once
+// and the references being copied to detect cycles.
+type copier struct {
+	op      *Options
+	copies  map[copyRef]reflect.Value
+	copying map[copyRef]bool
+}
+
+// deepCopy returns a deep copy of val of the same type.
+func (cli *anyClient) deepCopy(val reflect.Value) (reflect.Value, error) {
+	c := &copier{op: cli.options, copies: make(map[copyRef]reflect.Value), copying: make(map[copyRef]bool)}
+	return c.copy(val)
+}
+
+// copy returns a deep copy of val, the copy is settable for container kinds.
+func (c *copier) copy(val reflect.Value) (reflect.Value, error) {
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if val.Type() == locationType {
+			return val, nil // locations are immutable and shared, as time.Time does
+		}
+		ref, ok := refOf(val)
+		if !ok {
+			return reflect.Zero(val.Type()), nil
+		}
+		if out, ok := c.copies[ref]; ok {
+			return out, nil
+		}
+		if c.copying[ref] {
+			return reflect.Value{}, errors.Errorf(ErrCyclicValue, val.Type())
+		}
+		c.copying[ref] = true
+		defer delete(c.copying, ref)
+		return c.copyRef(val, ref)
+	case reflect.Interface:
+		out := reflect.New(val.Type()).Elem()
+		if val.IsNil() {
+			return out, nil
+		}
+		elem, err := c.copy(val.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out.Set(elem)
+		return out, nil
+	case reflect.Array:
+		out := reflect.New(val.Type()).Elem()
+		for i := 0; i < val.Len(); i++ {
+			elem, err := c.copy(val.Index(i))
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			out.Index(i).Set(elem)
+		}
+		return out, nil
+	case reflect.Struct:
+		return c.copyStruct(val)
+	default:
+		return val, nil
+	}
+}
+
+// copyRef copies a pointer, map or slice. The copy is remembered before its elements are copied
+// when shared references are copied once, so that cycles point to the copy.
+func (c *copier) copyRef(val reflect.Value, ref copyRef) (reflect.Value, error) {
+	switch val.Kind() {
+	case reflect.Ptr:
+		out := reflect.New(val.Type().Elem())
+		c.remember(ref, out)
+		elem, err := c.copy(val.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out.Elem().Set(elem)
+		return out, nil
+	case reflect.Map:
+		out := reflect.MakeMapWithSize(val.Type(), val.Len())
+		c.remember(ref, out)
+		iter := val.MapRange()
+		for iter.Next() {
+			key, err := c.copy(iter.Key())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			elem, err := c.copy(iter.Value())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			out.SetMapIndex(key, elem)
+		}
+		return out, nil
+	default:
+		out := reflect.MakeSlice(val.Type(), val.Len(), val.Len())
+		c.remember(ref, out)
+		for i := 0; i < val.Len(); i++ {
+			elem, err := c.copy(val.Index(i))
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			out.Index(i).Set(elem)
+		}
+		return out, nil
+	}
+}
+
+// copyStruct copies a struct. Unexported fields are copied deeply with SetExportedUnExported,
+// otherwise they keep the values of val.
+func (c *copier) copyStruct(val reflect.Value) (reflect.Value, error) {
+	in := reflect.New(val.Type()).Elem()
+	in.Set(val) // an addressable value, so that unexported fields can be read
+	out := reflect.New(val.Type()).Elem()
+	out.Set(val)
+	for i := 0; i < val.NumField(); i++ {
+		inField, outField := in.Field(i), out.Field(i)
+		if val.Type().Field(i).PkgPath != "" {
+			if !c.op.exportedUnExported {
+				continue
+			}
+			inField, outField = unexportedField(inField), unexportedField(outField)
+		}
+		copied, err := c.copy(inField)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		outField.Set(copied)
+	}
+	return out, nil
+}
+
+// remember keeps the copy of a reference when shared references are copied once.
+func (c *copier) remember(ref copyRef, out reflect.Value) {
+	if c.op.copyShared {
+		c.copies[ref] = out
+	}
+}
+
+// unexportedField returns a settable value of an addressable unexported field.
+// getUnexportedField returns the pointed value of pointer fields, so pointers are handled here.
+func unexportedField(field reflect.Value) reflect.Value {
+	if field.Kind() == reflect.Ptr {
+		return reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+	}
+	return getUnexportedField(field)
+}
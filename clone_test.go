@@ -0,0 +1,159 @@
+package goany
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type cloneNode struct {
+	Name  string     `json:"name"`
+	Next  *cloneNode `json:"next"`
+	Other *cloneNode `json:"other"`
+}
+
+type cloneDoc struct {
+	Title   string                 `json:"title"`
+	Tags    []string               `json:"tags"`
+	Meta    map[string]interface{} `json:"meta"`
+	Owner   *cloneNode             `json:"owner"`
+	Items   [2][]int               `json:"items"`
+	Extra   interface{}            `json:"extra"`
+	Created time.Time              `json:"created"`
+	secret  []byte
+}
+
+func newCloneDoc() cloneDoc {
+	return cloneDoc{
+		Title:   "doc",
+		Tags:    []string{"a", "b"},
+		Meta:    map[string]interface{}{"list": []interface{}{1, map[string]int{"x": 1}}},
+		Owner:   &cloneNode{Name: "ann"},
+		Items:   [2][]int{{1}, {2, 3}},
+		Extra:   map[string]string{"k": "v"},
+		Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		secret:  []byte("s"),
+	}
+}
+
+func TestClone(t *testing.T) {
+	t.Run("Test with nested values", func(t *testing.T) {
+		in := newCloneDoc()
+		out := Clone(in)
+		assert.Equal(t, in, out)
+
+		out.Tags[0] = "z"
+		out.Meta["list"].([]interface{})[1].(map[string]int)["x"] = 2
+		out.Owner.Name = "bob"
+		out.Items[1][0] = 9
+		out.Extra.(map[string]string)["k"] = "w"
+		assert.Equal(t, newCloneDoc(), in)
+
+		out.secret[0] = 'x'
+		assert.Equal(t, []byte("x"), in.secret)
+	})
+
+	t.Run("Test with unexported fields", func(t *testing.T) {
+		in := newCloneDoc()
+		out := Clone(in, *NewOptions().SetExportedUnExported(true))
+		assert.Equal(t, in, out)
+		out.secret[0] = 'x'
+		assert.Equal(t, []byte("s"), in.secret)
+
+		loc := time.FixedZone("UTC+8", 8*3600)
+		at := time.Date(2024, 1, 2, 3, 4, 5, 0, loc)
+		copied := Clone(at, *NewOptions().SetExportedUnExported(true))
+		assert.True(t, at.Equal(copied))
+		assert.Same(t, loc, copied.Location())
+	})
+
+	t.Run("Test with pointer and interface", func(t *testing.T) {
+		in := &cloneNode{Name: "a", Next: &cloneNode{Name: "b"}}
+		out := Clone(in)
+		assert.Equal(t, in, out)
+		assert.NotSame(t, in.Next, out.Next)
+
+		var v interface{} = []interface{}{map[string]interface{}{"a": 1}}
+		c := Clone(v)
+		c.([]interface{})[0].(map[string]interface{})["a"] = 2
+		assert.Equal(t, 1, v.([]interface{})[0].(map[string]interface{})["a"])
+
+		assert.Nil(t, Clone[map[string]int](nil))
+	})
+
+	t.Run("Test with shared and cyclic pointers", func(t *testing.T) {
+		shared := &cloneNode{Name: "shared"}
+		in := &cloneNode{Name: "a", Next: shared, Other: shared}
+		out := Clone(in)
+		assert.NotSame(t, out.Next, out.Other)
+
+		out = Clone(in, *NewOptions().SetCopyShared(true))
+		assert.Same(t, out.Next, out.Other)
+		assert.NotSame(t, shared, out.Next)
+
+		cyclic := &cloneNode{Name: "a"}
+		cyclic.Next = &cloneNode{Name: "b", Next: cyclic}
+		_, err := CloneE(cyclic)
+		assert.Equal(t, errors.Errorf(ErrCyclicValue, "*goany.cloneNode").Error(), err.Error())
+
+		copied, err := CloneE(cyclic, *NewOptions().SetCopyShared(true))
+		assert.NoError(t, err)
+		assert.Same(t, copied, copied.Next.Next)
+		assert.NotSame(t, cyclic, copied)
+
+		m := map[string]interface{}{}
+		m["self"] = m
+		cm, err := CloneE(m, *NewOptions().SetCopyShared(true))
+		assert.NoError(t, err)
+		cm["x"] = 1
+		assert.Equal(t, 1, cm["self"].(map[string]interface{})["x"])
+		assert.NotContains(t, m, "x")
+	})
+}
+
+func TestDeepCopy(t *testing.T) {
+	t.Run("Test with same type", func(t *testing.T) {
+		in := newCloneDoc()
+		var out cloneDoc
+		assert.NoError(t, DeepCopy(&out, &in))
+		assert.Equal(t, in, out)
+		out.Meta["list"] = nil
+		assert.NotNil(t, in.Meta["list"])
+	})
+
+	t.Run("Test with other type", func(t *testing.T) {
+		type view struct {
+			Title string                 `json:"title"`
+			Meta  map[string]interface{} `json:"meta"`
+			Extra interface{}            `json:"extra"`
+		}
+		in := newCloneDoc()
+		var out view
+		assert.NoError(t, DeepCopy(&out, in))
+		assert.Equal(t, "doc", out.Title)
+		out.Extra.(map[string]string)["k"] = "w"
+		out.Meta["list"].([]interface{})[0] = 5
+		assert.Equal(t, newCloneDoc(), in)
+
+		assert.Error(t, DeepCopy(out, in))
+
+		ops := []Options{*NewOptions()}
+		assert.NoError(t, DeepCopy(&out, in, ops...))
+		assert.False(t, ops[0].deepCopy)
+	})
+
+	t.Run("Test with deep copy option", func(t *testing.T) {
+		type holder struct {
+			Data interface{} `json:"data"`
+		}
+		in := holder{Data: map[string]int{"a": 1}}
+		var shared, copied holder
+		assert.NoError(t, ToAny(in, &shared))
+		assert.NoError(t, ToAny(in, &copied, *NewOptions().SetDeepCopy(true)))
+		in.Data.(map[string]int)["a"] = 2
+		assert.Equal(t, 2, shared.Data.(map[string]int)["a"])
+		assert.Equal(t, 1, copied.Data.(map[string]int)["a"])
+	})
+}
@@ -0,0 +1,119 @@
+package goany
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// decodeState is the state of one conversion shared by its clients: the count of decoded elements,
+// the path of the value being decoded, the references being decoded to detect cycles, and the outputs of decoded references.
+type decodeState struct {
+	jsonNumbers int // count of json strings being decoded, whose numbers are json.Number
+	elements    int
+	path        []flatSegment
+	decoding    map[copyRef]int
+	outputs     map[outputRef]reflect.Value
+}
+
+// outputRef identifies the output of an input reference by its type.
+type outputRef struct {
+	ref copyRef
+	typ reflect.Type
+}
+
+// decodeAt decodes in into outVal as the value at a segment below the value being decoded.
+// The segment is part of the path used by the max depth option, by limit errors and by cycle errors.
+func (cli *anyClient) decodeAt(segment flatSegment, in interface{}, outVal reflect.Value) error {
+	state := cli.state
+	state.path = append(state.path, segment)
+	defer func() { state.path = state.path[:len(state.path)-1] }()
+	if err := cli.countElement(); err != nil {
+		return err
+	}
+	return cli.decodeAny(in, outVal)
+}
+
+// decodeRef checks an input reference before it is decoded. It returns true if the output was set,
+// from the output of the same reference with CyclePreserve or to nil with CycleNil, and a function to call
+// when the reference is decoded. Decoding a reference again below itself is a cycle.
+func (cli *anyClient) decodeRef(in interface{}, outVal reflect.Value) (bool, func(), error) {
+	ref, ok := refOf(reflect.ValueOf(in))
+	if !ok {
+		return false, nil, nil
+	}
+	state := cli.state
+	preserve := cli.options.cyclePolicy == CyclePreserve
+	if preserve && (outVal.Kind() == reflect.Ptr || outVal.Kind() == reflect.Map) {
+		if out, ok := state.outputs[outputRef{ref: ref, typ: outVal.Type()}]; ok {
+			outVal.Set(out)
+			return true, nil, nil
+		}
+	}
+	if depth, ok := state.decoding[ref]; ok {
+		if depth == len(state.path) { // the same value passed on to another decoder, such as decodePtr
+			if outVal.CanAddr() {
+				cli.keepOutput(in, outVal.Addr())
+			}
+			return false, nil, nil
+		}
+		if cli.options.cyclePolicy == CycleNil {
+			outVal.Set(reflect.Zero(outVal.Type()))
+			return true, nil, nil
+		}
+		return false, nil, errors.Errorf(ErrCyclicPath, ref.typ, state.pathString())
+	}
+
+	if state.decoding == nil {
+		state.decoding = make(map[copyRef]int)
+	}
+	state.decoding[ref] = len(state.path)
+	if outVal.CanAddr() {
+		cli.keepOutput(in, outVal.Addr())
+	}
+	return false, func() { delete(state.decoding, ref) }, nil
+}
+
+// keepOutput remembers out as the output of an input reference with CyclePreserve, so that other
+// references to the input are decoded to the same output.
+func (cli *anyClient) keepOutput(in interface{}, out reflect.Value) {
+	ref, ok := refOf(reflect.ValueOf(in))
+	if !ok || cli.options.cyclePolicy != CyclePreserve {
+		return
+	}
+	state := cli.state
+	if state.outputs == nil {
+		state.outputs = make(map[outputRef]reflect.Value)
+	}
+	state.outputs[outputRef{ref: ref, typ: out.Type()}] = out
+}
+
+// pathString formats the path of the value being decoded, see formatPath.
+func (state *decodeState) pathString() string {
+	return formatPath(state.path)
+}
+
+// formatPath formats path segments like a flat key, such as "orders[2].items".
+func formatPath(segments []flatSegment) string {
+	path := ""
+	for _, segment := range segments {
+		if segment.isIndex {
+			path += "[" + strconv.Itoa(segment.index) + "]"
+		} else {
+			path = joinFlatKey(path, segment.key, ".")
+		}
+	}
+	return path
+}
+
+// keySegment returns the path segment of a map key.
+func keySegment(k reflect.Value) flatSegment {
+	return flatSegment{key: fmt.Sprint(k.Interface())}
+}
+
+// indexSegment returns the path segment of a list index.
+func indexSegment(i int) flatSegment {
+	return flatSegment{index: i, isIndex: true}
+}
@@ -0,0 +1,117 @@
+package goany
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type cycleNode struct {
+	Name string     `json:"name"`
+	Next *cycleNode `json:"next"`
+}
+
+type cycleTree map[string]cycleTree
+
+func newCycle() *cycleNode {
+	a := &cycleNode{Name: "a"}
+	a.Next = &cycleNode{Name: "b", Next: a}
+	return a
+}
+
+func TestCyclePolicy(t *testing.T) {
+	t.Run("Test with error", func(t *testing.T) {
+		var out cycleNode
+		err := ToAny(newCycle(), &out)
+		assert.Equal(t, errors.Errorf(ErrCyclicPath, "*goany.cycleNode", "next.next").Error(), err.Error())
+
+		var m map[string]interface{}
+		err = ToAny(newCycle(), &m, *NewOptions().SetStructToMapDetail(true))
+		assert.Equal(t, errors.Errorf(ErrCyclicPath, "*goany.cycleNode", "next.next").Error(), err.Error())
+
+		tree := cycleTree{}
+		tree["self"] = tree
+		var outTree cycleTree
+		err = ToAny(tree, &outTree)
+		assert.Equal(t, errors.Errorf(ErrCyclicPath, "goany.cycleTree", "self").Error(), err.Error())
+	})
+
+	t.Run("Test with nil", func(t *testing.T) {
+		var out cycleNode
+		err := ToAny(newCycle(), &out, *NewOptions().SetCyclePolicy(CycleNil))
+		assert.NoError(t, err)
+		assert.Equal(t, cycleNode{Name: "a", Next: &cycleNode{Name: "b"}}, out)
+
+		var m map[string]interface{}
+		err = ToAny(newCycle(), &m, *NewOptions().SetCyclePolicy(CycleNil).SetStructToMapDetail(true))
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"name": "a", "next": map[string]interface{}{"name": "b", "next": nil}}, m)
+	})
+
+	t.Run("Test with preserve", func(t *testing.T) {
+		var out cycleNode
+		err := ToAny(newCycle(), &out, *NewOptions().SetCyclePolicy(CyclePreserve))
+		assert.NoError(t, err)
+		assert.Equal(t, "b", out.Next.Name)
+		assert.Same(t, &out, out.Next.Next)
+
+		tree := cycleTree{}
+		tree["self"] = tree
+		var outTree cycleTree
+		err = ToAny(tree, &outTree, *NewOptions().SetCyclePolicy(CyclePreserve))
+		assert.NoError(t, err)
+		assert.Equal(t, reflect.ValueOf(outTree).Pointer(), reflect.ValueOf(outTree["self"]).Pointer())
+	})
+
+	t.Run("Test with shared pointers", func(t *testing.T) {
+		type pair struct {
+			Left  *cycleNode `json:"left"`
+			Right *cycleNode `json:"right"`
+		}
+		shared := &cycleNode{Name: "s"}
+		in := pair{Left: shared, Right: shared}
+
+		var out pair
+		assert.NoError(t, ToAny(in, &out))
+		assert.Equal(t, in, out)
+		assert.NotSame(t, out.Left, out.Right)
+
+		assert.NoError(t, ToAny(in, &out, *NewOptions().SetCyclePolicy(CyclePreserve)))
+		assert.Same(t, out.Left, out.Right)
+		assert.NotSame(t, shared, out.Left)
+	})
+}
+
+func TestMaxDepth(t *testing.T) {
+	in := map[string]interface{}{
+		"a": map[string]interface{}{"b": []interface{}{map[string]interface{}{"c": 1}}},
+	}
+
+	t.Run("Test with deep value", func(t *testing.T) {
+		var out map[string]map[string][]map[string]int
+		err := ToAny(in, &out, *NewOptions().SetMaxDepth(3))
+		assert.Equal(t, errors.Errorf(ErrMaxDepth, 3, "a.b[0].c").Error(), err.Error())
+
+		err = ToAny(in, &out, *NewOptions().SetMaxDepth(4))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, out["a"]["b"][0]["c"])
+	})
+
+	t.Run("Test with struct", func(t *testing.T) {
+		node := &cycleNode{Name: "a", Next: &cycleNode{Name: "b", Next: &cycleNode{Name: "c"}}}
+		var out cycleNode
+		err := ToAny(node, &out, *NewOptions().SetMaxDepth(2))
+		assert.Equal(t, errors.Errorf(ErrMaxDepth, 2, "next.next.name").Error(), err.Error())
+
+		err = ToAny(newCycle(), &out, *NewOptions().SetMaxDepth(10).SetCyclePolicy(CycleNil))
+		assert.NoError(t, err)
+	})
+
+	t.Run("Test without limit", func(t *testing.T) {
+		var out interface{}
+		assert.NoError(t, ToAny(in, &out))
+		assert.Equal(t, in, out)
+	})
+}
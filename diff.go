@@ -0,0 +1,282 @@
+package goany
+
+import (
+	"bytes"
+	"reflect"
+)
+
+// Kinds of a Change, named like the operations of a JSON patch.
+const (
+	ChangeAdd     = "add"     // the value is only in the new value
+	ChangeRemove  = "remove"  // the value is only in the old value
+	ChangeReplace = "replace" // the value differs between the old and the new value
+)
+
+// Change is a difference between two values found by Diff. Path is the path of the value, such as
+// "servers[0].port", it is empty for the values themselves. Old is nil when a value is added,
+// and New is nil when a value is removed.
+type Change struct {
+	Kind string      `json:"kind"`
+	Path string      `json:"path"`
+	Old  interface{} `json:"old"`
+	New  interface{} `json:"new"`
+}
+
+// change is a Change with the segments of its path.
+type change struct {
+	Change
+	segments []flatSegment
+}
+
+// Diff returns the changes from a to b, see DiffE.
+func Diff(a, b interface{}, op ...Options) []Change {
+	changes, _ := DiffE(a, b, op...)
+	return changes
+}
+
+// DiffE returns the changes from a to b. Structs, maps, lists, pointers and interfaces are compared
+// recursively, struct fields by their tag names with embedded fields promoted, map keys in natural order
+// and lists by index. Values of different types are replaced, unless SetDiffConvert is set, then b is
+// converted to the type of a first, which is an error if it fails.
+func DiffE(a, b interface{}, op ...Options) ([]Change, error) {
+	changes, err := diffValues(a, b, op...)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Change, len(changes))
+	for i, c := range changes {
+		out[i] = c.Change
+	}
+	return out, nil
+}
+
+// diffValues returns the changes from a to b with the segments of their paths.
+func diffValues(a, b interface{}, op ...Options) ([]change, error) {
+	cli := newAnyClient(op...)
+	aVal, bVal := reflect.ValueOf(a), reflect.ValueOf(b)
+	if cli.options.diffConvert && aVal.IsValid() && bVal.IsValid() && aVal.Type() != bVal.Type() {
+		converted := reflect.New(aVal.Type()).Elem()
+		if err := cli.decodeAny(b, converted); err != nil {
+			return nil, err
+		}
+		bVal = converted
+	}
+	d := &differ{cli: cli, visiting: make(map[diffPair]bool)}
+	d.diff(aVal, bVal)
+	return d.changes, nil
+}
+
+// differ collects the changes between two values. visiting holds the pairs of references being
+// compared, a pair met again below itself is a cycle and is not compared again.
+type differ struct {
+	cli      *anyClient
+	path     []flatSegment
+	changes  []change
+	visiting map[diffPair]bool
+}
+
+// diffPair is a pair of references compared by a differ.
+type diffPair struct {
+	a, b copyRef
+}
+
+// add records a change at the current path.
+func (d *differ) add(kind string, a, b reflect.Value) {
+	c := change{Change: Change{Kind: kind, Path: formatPath(d.path)}}
+	c.segments = append([]flatSegment(nil), d.path...)
+	if a.IsValid() && a.CanInterface() {
+		c.Old = a.Interface()
+	}
+	if b.IsValid() && b.CanInterface() {
+		c.New = b.Interface()
+	}
+	d.changes = append(d.changes, c)
+}
+
+// at compares a and b at a segment below the current path.
+func (d *differ) at(segment flatSegment, a, b reflect.Value) {
+	d.path = append(d.path, segment)
+	d.diff(a, b)
+	d.path = d.path[:len(d.path)-1]
+}
+
+// diff compares a and b at the current path.
+func (d *differ) diff(a, b reflect.Value) {
+	aRef, aOK := diffRef(a)
+	bRef, bOK := diffRef(b)
+	if aOK && bOK {
+		pair := diffPair{a: aRef, b: bRef}
+		if d.visiting[pair] {
+			return
+		}
+		d.visiting[pair] = true
+		defer delete(d.visiting, pair)
+	}
+	a, b = diffIndirect(a), diffIndirect(b)
+	if !a.IsValid() || !b.IsValid() {
+		if a.IsValid() || b.IsValid() {
+			d.add(ChangeReplace, a, b)
+		}
+		return
+	}
+	if a.Type() != b.Type() {
+		converted := reflect.New(a.Type()).Elem()
+		if !d.cli.options.diffConvert || d.cli.decodeAny(b.Interface(), converted) != nil {
+			d.add(ChangeReplace, a, b)
+			return
+		}
+		b = converted
+	}
+
+	switch a.Kind() {
+	case reflect.Struct:
+		if isFlatLeaf(a) {
+			break
+		}
+		d.diffStruct(a, b)
+		return
+	case reflect.Map:
+		d.diffMap(a, b)
+		return
+	case reflect.Slice, reflect.Array:
+		if a.Type().Elem().Kind() == reflect.Uint8 {
+			if !bytes.Equal(bytesOf(a), bytesOf(b)) {
+				d.add(ChangeReplace, a, b)
+			}
+			return
+		}
+		d.diffList(a, b)
+		return
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		if a.Pointer() != b.Pointer() {
+			d.add(ChangeReplace, a, b)
+		}
+		return
+	}
+	if at, ok := asTime(a.Interface(), nil); ok {
+		// the same instant in another location is not a change
+		if bt, ok := asTime(b.Interface(), nil); ok && at.Equal(bt) {
+			return
+		}
+	}
+	if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+		d.add(ChangeReplace, a, b)
+	}
+}
+
+// diffStruct compares the fields of two structs of the same type by their tag names,
+// the fields of embedded structs without a tag are compared as fields of the struct.
+func (d *differ) diffStruct(a, b reflect.Value) {
+	a, b = addressable(a), addressable(b)
+	for i := 0; i < a.NumField(); i++ {
+		field := fieldInfo{fieldStruct: a.Type().Field(i)}
+		field.fieldName = GetFieldNameByTag(field.fieldStruct, d.cli.options.tagName)
+		if !field.canUse(*d.cli.options) {
+			continue
+		}
+		aField, bField := a.Field(i), b.Field(i)
+		if field.fieldStruct.PkgPath != "" {
+			aField, bField = unexportedField(aField), unexportedField(bField)
+		}
+		if field.fieldStruct.Anonymous && field.fieldStruct.Tag.Get(d.cli.options.tagName) == "" {
+			if aEmbed, bEmbed := diffIndirect(aField), diffIndirect(bField); aEmbed.Kind() == reflect.Struct && bEmbed.Kind() == reflect.Struct {
+				d.diffStruct(aEmbed, bEmbed) // embedded fields are promoted
+				continue
+			}
+		}
+		d.at(flatSegment{key: field.fieldName}, aField, bField)
+	}
+}
+
+// diffMap compares the values of two maps by key, keys of only one map are removed or added.
+func (d *differ) diffMap(a, b reflect.Value) {
+	op := *d.cli.options
+	if op.mapKeyOrder == MapOrderNone {
+		op.mapKeyOrder = MapOrderNatural
+	}
+	keys := op.mapKeys(reflect.ValueOf(mergeKeys(a, b)))
+	for _, k := range keys {
+		aElem, bElem := a.MapIndex(k), b.MapIndex(k)
+		switch {
+		case !bElem.IsValid():
+			d.path = append(d.path, keySegment(k))
+			d.add(ChangeRemove, aElem, reflect.Value{})
+			d.path = d.path[:len(d.path)-1]
+		case !aElem.IsValid():
+			d.path = append(d.path, keySegment(k))
+			d.add(ChangeAdd, reflect.Value{}, bElem)
+			d.path = d.path[:len(d.path)-1]
+		default:
+			d.at(keySegment(k), aElem, bElem)
+		}
+	}
+}
+
+// diffList compares the elements of two lists by index, the elements after the end of the shorter
+// list are added, or removed from the last one so that the indexes of the changes stay valid in order.
+func (d *differ) diffList(a, b reflect.Value) {
+	for i := 0; i < a.Len() && i < b.Len(); i++ {
+		d.at(indexSegment(i), a.Index(i), b.Index(i))
+	}
+	for i := a.Len() - 1; i >= b.Len(); i-- {
+		d.path = append(d.path, indexSegment(i))
+		d.add(ChangeRemove, a.Index(i), reflect.Value{})
+		d.path = d.path[:len(d.path)-1]
+	}
+	for i := a.Len(); i < b.Len(); i++ {
+		d.path = append(d.path, indexSegment(i))
+		d.add(ChangeAdd, reflect.Value{}, b.Index(i))
+		d.path = d.path[:len(d.path)-1]
+	}
+}
+
+// mergeKeys returns a map holding the keys of the maps a and b, so that they can be ordered together.
+func mergeKeys(a, b reflect.Value) interface{} {
+	keys := reflect.MakeMapWithSize(reflect.MapOf(a.Type().Key(), reflect.TypeOf(struct{}{})), a.Len())
+	for _, m := range []reflect.Value{a, b} {
+		iter := m.MapRange()
+		for iter.Next() {
+			keys.SetMapIndex(iter.Key(), reflect.ValueOf(struct{}{}))
+		}
+	}
+	return keys.Interface()
+}
+
+// diffRef returns the reference of a pointer, map or slice, also held in an interface.
+func diffRef(val reflect.Value) (copyRef, bool) {
+	for val.Kind() == reflect.Interface && !val.IsNil() {
+		val = val.Elem()
+	}
+	return refOf(val)
+}
+
+// diffIndirect returns the value a pointer or interface holds, and an invalid value for nil.
+func diffIndirect(val reflect.Value) reflect.Value {
+	for val.IsValid() && (val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface) {
+		if val.IsNil() {
+			return reflect.Value{}
+		}
+		val = val.Elem()
+	}
+	return val
+}
+
+// addressable returns val, or an addressable copy of it, so that its unexported fields can be read.
+func addressable(val reflect.Value) reflect.Value {
+	if val.CanAddr() {
+		return val
+	}
+	out := reflect.New(val.Type()).Elem()
+	out.Set(val)
+	return out
+}
+
+// bytesOf returns the bytes of a byte slice or array.
+func bytesOf(val reflect.Value) []byte {
+	if val.Kind() == reflect.Slice {
+		return val.Bytes()
+	}
+	out := make([]byte, val.Len())
+	reflect.Copy(reflect.ValueOf(out), val)
+	return out
+}
@@ -0,0 +1,138 @@
+package goany
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type DiffBase struct {
+	Env string `json:"env"`
+}
+
+type diffServer struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+}
+
+type diffConfig struct {
+	DiffBase
+	Name    string            `json:"name"`
+	Servers []diffServer      `json:"servers"`
+	Labels  map[string]string `json:"labels"`
+	Backup  *diffServer       `json:"backup"`
+	Since   time.Time         `json:"since"`
+	Key     []byte            `json:"key"`
+	Skip    string            `json:"-"`
+	secret  string
+}
+
+func TestDiff(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	old := diffConfig{
+		DiffBase: DiffBase{Env: "dev"},
+		Name:     "app",
+		Servers:  []diffServer{{Host: "a", Port: 80}, {Host: "b", Port: 81}},
+		Labels:   map[string]string{"team": "core", "tier": "1"},
+		Since:    since,
+		Key:      []byte("k1"),
+		Skip:     "x",
+		secret:   "s1",
+	}
+
+	t.Run("Test with equal values", func(t *testing.T) {
+		assert.Empty(t, Diff(old, old))
+		assert.Empty(t, Diff(&old, old))
+		assert.Empty(t, Diff(nil, nil))
+	})
+
+	t.Run("Test with struct changes", func(t *testing.T) {
+		updated := old
+		updated.Env = "prod"
+		updated.Servers = []diffServer{{Host: "a", Port: 8080}}
+		updated.Labels = map[string]string{"team": "core", "zone": "eu"}
+		updated.Backup = &diffServer{Host: "bk"}
+		updated.Since = since.Add(time.Hour)
+		updated.Key = []byte("k2")
+		updated.Skip = "y"
+		updated.secret = "s2"
+
+		assert.Equal(t, []Change{
+			{Kind: ChangeReplace, Path: "env", Old: "dev", New: "prod"},
+			{Kind: ChangeReplace, Path: "servers[0].port", Old: 80, New: 8080},
+			{Kind: ChangeRemove, Path: "servers[1]", Old: diffServer{Host: "b", Port: 81}},
+			{Kind: ChangeRemove, Path: "labels.tier", Old: "1"},
+			{Kind: ChangeAdd, Path: "labels.zone", New: "eu"},
+			{Kind: ChangeReplace, Path: "backup", New: diffServer{Host: "bk"}},
+			{Kind: ChangeReplace, Path: "since", Old: since, New: since.Add(time.Hour)},
+			{Kind: ChangeReplace, Path: "key", Old: []byte("k1"), New: []byte("k2")},
+		}, Diff(old, updated))
+
+		changes := Diff(old, updated, *NewOptions().SetExportedUnExported(true))
+		assert.Equal(t, Change{Kind: ChangeReplace, Path: "secret", Old: "s1", New: "s2"}, changes[len(changes)-1])
+	})
+
+	t.Run("Test with maps and lists", func(t *testing.T) {
+		a := map[string]interface{}{"n": 1, "list": []interface{}{1, 2}, "m": map[string]interface{}{"x": true}}
+		b := map[string]interface{}{"n": 1.0, "list": []interface{}{1, 2, 3}, "m": map[string]interface{}{"x": false}}
+		assert.Equal(t, []Change{
+			{Kind: ChangeAdd, Path: "list[2]", New: 3},
+			{Kind: ChangeReplace, Path: "m.x", Old: true, New: false},
+			{Kind: ChangeReplace, Path: "n", Old: 1, New: 1.0},
+		}, Diff(a, b))
+
+		assert.Equal(t, []Change{{Kind: ChangeReplace, Old: 1, New: "1"}}, Diff(1, "1"))
+		assert.Equal(t, []Change{{Kind: ChangeReplace, Path: "10", Old: 10, New: 11}}, Diff(map[int]int{2: 2, 10: 10}, map[int]int{2: 2, 10: 11}))
+	})
+
+	t.Run("Test with convert", func(t *testing.T) {
+		op := NewOptions().SetDiffConvert(true)
+		in := map[string]interface{}{
+			"env":     "dev",
+			"name":    "app",
+			"servers": []map[string]interface{}{{"host": "a", "port": "80"}, {"host": "b", "port": 82}},
+			"labels":  map[string]string{"team": "core", "tier": "1"},
+			"since":   "2024-01-01",
+			"key":     []byte("k1"),
+		}
+		changes, err := DiffE(old, in, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, []Change{{Kind: ChangeReplace, Path: "servers[1].port", Old: 81, New: 82}}, changes)
+
+		assert.Empty(t, Diff(map[string]interface{}{"n": 1}, map[string]interface{}{"n": "1"}, *op))
+
+		_, err = DiffE(old, "not json", *op)
+		assert.Error(t, err)
+	})
+
+	t.Run("Test with times in other locations", func(t *testing.T) {
+		shanghai := time.FixedZone("CST", 8*3600)
+		moved := old
+		moved.Since = since.In(shanghai)
+		assert.Empty(t, Diff(old, moved))
+		assert.Empty(t, Diff(map[string]interface{}{"at": since}, map[string]interface{}{"at": since.In(shanghai)}))
+
+		moved.Since = since.Add(time.Second).In(shanghai)
+		assert.Equal(t, []Change{{Kind: ChangeReplace, Path: "since", Old: since, New: moved.Since}}, Diff(old, moved))
+	})
+
+	t.Run("Test with cyclic values", func(t *testing.T) {
+		type node struct {
+			Name string `json:"name"`
+			Next *node  `json:"next"`
+		}
+		a := &node{Name: "a"}
+		a.Next = &node{Name: "b", Next: a}
+		b := &node{Name: "a"}
+		b.Next = &node{Name: "c", Next: b}
+		assert.Equal(t, []Change{{Kind: ChangeReplace, Path: "next.name", Old: "b", New: "c"}}, Diff(a, b))
+		assert.Empty(t, Diff(a, a))
+
+		m := map[string]interface{}{"n": 1}
+		m["self"] = m
+		other := map[string]interface{}{"n": 2}
+		other["self"] = other
+		assert.Equal(t, []Change{{Kind: ChangeReplace, Path: "n", Old: 1, New: 2}}, Diff(m, other))
+	})
+}
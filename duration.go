@@ -0,0 +1,274 @@
+package goany
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// extendedDurationUnits are the units accepted in duration strings in addition to the ones of time.ParseDuration.
+var extendedDurationUnits = map[string]time.Duration{
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+// ToDuration attempts to convert an interface value to a time.Duration value
+func ToDuration(v interface{}, op ...Options) time.Duration {
+	d, _ := ToDurationE(v, op...)
+	return d
+}
+
+// ToDurationE attempts to convert an interface value to a time.Duration value.
+func ToDurationE(v interface{}, op ...Options) (time.Duration, error) {
+	var out time.Duration
+	err := toScalarE(v, &out, op)
+	return out, err
+}
+
+// decodeDuration decodes an input value into a time.Duration output value
+func (cli *anyClient) decodeDuration(in interface{}, outVal reflect.Value) error {
+	d, err := toDurationE(in, *cli.options)
+	if err != nil {
+		return err
+	}
+	outVal.SetInt(int64(d))
+	return nil
+}
+
+// toDurationE converts an interface value to time.Duration. Durations are returned as they are,
+// strings are parsed by parseDuration, and numbers are counted in the duration unit of op.
+func toDurationE(in interface{}, op Options) (time.Duration, error) {
+	in = Indirect(in)
+	if CheckInIsNil(in) {
+		return 0, nil
+	}
+	if d, ok := in.(time.Duration); ok {
+		return d, nil
+	}
+	if n, ok := in.(json.Number); ok {
+		return scaleDuration(n.String(), op.unitOfDuration(), in)
+	}
+	switch inVal := reflect.ValueOf(in); inVal.Kind() {
+	case reflect.String:
+		return parseDuration(inVal.String(), op)
+	case reflect.Slice:
+		if b, ok := in.([]uint8); ok {
+			return parseDuration(string(b), op)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return intToDuration(inVal.Int(), op.unitOfDuration(), in)
+	}
+	f, err := toFloat64E(in, op)
+	if err != nil {
+		return 0, errors.Errorf(ErrUnableConvertDuration, in)
+	}
+	return floatToDuration(f*float64(op.unitOfDuration()), in)
+}
+
+// parseDuration parses a duration string. It accepts the format of time.ParseDuration extended
+// with the units "d" and "w", ISO-8601 durations such as "PT1H30M", and plain numbers counted
+// in the duration unit of op.
+func parseDuration(s string, op Options) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	if strings.HasPrefix(strings.TrimLeft(s, "+-"), "P") {
+		return parseISODuration(s)
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return scaleDuration(s, op.unitOfDuration(), s)
+	}
+	return parseExtendedDuration(s)
+}
+
+// parseExtendedDuration parses a duration string holding "d" or "w" units, such as "1d12h" or "-2w".
+// Every part is parsed by time.ParseDuration except the extended units.
+func parseExtendedDuration(s string) (time.Duration, error) {
+	rest, neg := s, false
+	if strings.HasPrefix(rest, "-") || strings.HasPrefix(rest, "+") {
+		rest, neg = rest[1:], rest[0] == '-'
+	}
+	if rest == "" {
+		return 0, errors.Errorf(ErrUnableConvertDuration, s)
+	}
+	var total time.Duration
+	for rest != "" {
+		i := strings.IndexFunc(rest, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+		if i <= 0 {
+			return 0, errors.Errorf(ErrUnableConvertDuration, s)
+		}
+		j := i + strings.IndexFunc(rest[i:], func(r rune) bool { return (r >= '0' && r <= '9') || r == '.' })
+		if j < i {
+			j = len(rest)
+		}
+		var d time.Duration
+		var err error
+		if u, ok := extendedDurationUnits[rest[i:j]]; ok {
+			d, err = scaleDuration(rest[:i], u, s)
+		} else if d, err = time.ParseDuration(rest[:j]); err != nil {
+			err = errors.Errorf(ErrUnableConvertDuration, s)
+		}
+		if err != nil {
+			return 0, err
+		}
+		if total, err = addDuration(total, d, s); err != nil {
+			return 0, err
+		}
+		rest = rest[j:]
+	}
+	if neg {
+		total = -total
+	}
+	return total, nil
+}
+
+// parseISODuration parses an ISO-8601 duration such as "PT1H30M", "P1DT12H" or "P2W".
+// Years and months have no fixed length and are rejected.
+func parseISODuration(s string) (time.Duration, error) {
+	rest, neg := s, false
+	if strings.HasPrefix(rest, "-") || strings.HasPrefix(rest, "+") {
+		rest, neg = rest[1:], rest[0] == '-'
+	}
+	rest = strings.TrimPrefix(rest, "P")
+	if rest == "" || rest == "T" {
+		return 0, errors.Errorf(ErrUnableConvertDuration, s)
+	}
+	var total time.Duration
+	inTime := false
+	for rest != "" {
+		if rest[0] == 'T' {
+			if inTime {
+				return 0, errors.Errorf(ErrUnableConvertDuration, s)
+			}
+			inTime, rest = true, rest[1:]
+			continue
+		}
+		i := strings.IndexFunc(rest, func(r rune) bool { return (r < '0' || r > '9') && r != '.' && r != ',' })
+		if i <= 0 {
+			return 0, errors.Errorf(ErrUnableConvertDuration, s)
+		}
+		var unit time.Duration
+		switch designator := rest[i]; {
+		case !inTime && designator == 'W':
+			unit = 7 * 24 * time.Hour
+		case !inTime && designator == 'D':
+			unit = 24 * time.Hour
+		case inTime && designator == 'H':
+			unit = time.Hour
+		case inTime && designator == 'M':
+			unit = time.Minute
+		case inTime && designator == 'S':
+			unit = time.Second
+		default:
+			return 0, errors.Errorf(ErrUnableConvertDuration, s)
+		}
+		d, err := scaleDuration(strings.Replace(rest[:i], ",", ".", 1), unit, s)
+		if err != nil {
+			return 0, err
+		}
+		if total, err = addDuration(total, d, s); err != nil {
+			return 0, err
+		}
+		rest = rest[i+1:]
+	}
+	if neg {
+		total = -total
+	}
+	return total, nil
+}
+
+// scaleDuration multiplies a decimal number by unit. Integers are multiplied exactly,
+// numbers with a fraction are rounded to the nearest nanosecond.
+func scaleDuration(num string, unit time.Duration, in interface{}) (time.Duration, error) {
+	if n, err := strconv.ParseInt(num, 10, 64); err == nil {
+		return intToDuration(n, unit, in)
+	}
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, errors.Errorf(ErrUnableConvertDuration, in)
+	}
+	return floatToDuration(f*float64(unit), in)
+}
+
+// addDuration adds two non-negative durations, checking the range.
+func addDuration(a, b time.Duration, in interface{}) (time.Duration, error) {
+	if a > math.MaxInt64-b {
+		return 0, errors.Errorf(ErrValueOverflow, in, "duration")
+	}
+	return a + b, nil
+}
+
+// intToDuration multiplies n by unit, checking the range.
+func intToDuration(n int64, unit time.Duration, in interface{}) (time.Duration, error) {
+	if n > math.MaxInt64/int64(unit) || n < math.MinInt64/int64(unit) {
+		return 0, errors.Errorf(ErrValueOverflow, in, "duration")
+	}
+	return time.Duration(n) * unit, nil
+}
+
+// unitOfDuration returns the duration unit, time.Nanosecond if none is set.
+func (op *Options) unitOfDuration() time.Duration {
+	if op.durationUnit <= 0 {
+		return time.Nanosecond
+	}
+	return op.durationUnit
+}
+
+// floatToDuration converts a number of nanoseconds to a time.Duration, checking the range.
+func floatToDuration(f float64, in interface{}) (time.Duration, error) {
+	f = math.Round(f)
+	if math.IsNaN(f) || f >= math.MaxInt64 || f < math.MinInt64 {
+		return 0, errors.Errorf(ErrValueOverflow, in, "duration")
+	}
+	return time.Duration(f), nil
+}
+
+// formatDuration renders a duration in the duration style of op.
+func formatDuration(d time.Duration, op Options) string {
+	switch op.durationStyle {
+	case DurationStyleGo:
+		return d.String()
+	case DurationStyleISO:
+		return formatISODuration(d)
+	case DurationStyleNumber:
+		if unit := op.unitOfDuration(); unit != time.Nanosecond {
+			return strconv.FormatFloat(float64(d)/float64(unit), 'f', -1, 64)
+		}
+	}
+	return strconv.FormatInt(int64(d), 10)
+}
+
+// formatISODuration renders a duration as an ISO-8601 duration with hours, minutes and seconds,
+// such as "PT1H30M" or "-PT0.5S".
+func formatISODuration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+	var b strings.Builder
+	u := uint64(d)
+	if d < 0 {
+		b.WriteByte('-')
+		u = -u
+	}
+	b.WriteString("PT")
+	hours, u := u/uint64(time.Hour), u%uint64(time.Hour)
+	minutes, u := u/uint64(time.Minute), u%uint64(time.Minute)
+	if hours > 0 {
+		b.WriteString(strconv.FormatUint(hours, 10) + "H")
+	}
+	if minutes > 0 {
+		b.WriteString(strconv.FormatUint(minutes, 10) + "M")
+	}
+	if u > 0 {
+		b.WriteString(strconv.FormatFloat(float64(u)/float64(time.Second), 'f', -1, 64) + "S")
+	}
+	return b.String()
+}
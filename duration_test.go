@@ -0,0 +1,112 @@
+package goany
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToDurationE(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    interface{}
+		op       *Options
+		expected time.Duration
+		hasErr   bool
+	}{
+		{name: "nil", input: nil, expected: 0},
+		{name: "duration", input: 90 * time.Second, expected: 90 * time.Second},
+		{name: "go string", input: "1m30s", expected: 90 * time.Second},
+		{name: "day", input: "1d12h", expected: 36 * time.Hour},
+		{name: "week", input: "-2w", expected: -14 * 24 * time.Hour},
+		{name: "fraction day", input: "1.5d", expected: 36 * time.Hour},
+		{name: "iso", input: "PT1H30M", expected: 90 * time.Minute},
+		{name: "iso day", input: "P1DT0.5S", expected: 24*time.Hour + 500*time.Millisecond},
+		{name: "iso week", input: "P2W", expected: 14 * 24 * time.Hour},
+		{name: "iso negative", input: "-PT10S", expected: -10 * time.Second},
+		{name: "int default nanosecond", input: 90, expected: 90},
+		{name: "int second", input: 90, op: NewOptions().SetDurationUnit(time.Second), expected: 90 * time.Second},
+		{name: "float second", input: 1.5, op: NewOptions().SetDurationUnit(time.Second), expected: 1500 * time.Millisecond},
+		{name: "string number", input: "90", op: NewOptions().SetDurationUnit(time.Millisecond), expected: 90 * time.Millisecond},
+		{name: "json number", input: json.Number("2"), op: NewOptions().SetDurationUnit(time.Minute), expected: 2 * time.Minute},
+		{name: "iso year", input: "P1Y", hasErr: true},
+		{name: "iso month in date", input: "P1M", hasErr: true},
+		{name: "invalid", input: "1x", hasErr: true},
+		{name: "overflow", input: "300000w", hasErr: true},
+		{name: "int overflow", input: int64(1) << 62, op: NewOptions().SetDurationUnit(time.Second), hasErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.op == nil {
+				tt.op = NewOptions()
+			}
+			out, err := ToDurationE(tt.input, *tt.op)
+			if tt.hasErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, out)
+		})
+	}
+}
+
+func TestDurationToString(t *testing.T) {
+	d := 90*time.Minute + 500*time.Millisecond
+
+	v, err := ToStringE(d)
+	assert.NoError(t, err)
+	assert.Equal(t, "5400500000000", v)
+
+	v, err = ToStringE(time.Duration(5), *NewOptions().SetDurationUnit(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, "5", v)
+
+	v, err = ToStringE(d, *NewOptions().SetDurationStyle(DurationStyleGo))
+	assert.NoError(t, err)
+	assert.Equal(t, "1h30m0.5s", v)
+
+	v, err = ToStringE(d, *NewOptions().SetDurationStyle(DurationStyleISO))
+	assert.NoError(t, err)
+	assert.Equal(t, "PT1H30M0.5S", v)
+
+	v, err = ToStringE(-d, *NewOptions().SetDurationStyle(DurationStyleISO))
+	assert.NoError(t, err)
+	assert.Equal(t, "-PT1H30M0.5S", v)
+
+	v, err = ToStringE(time.Duration(0), *NewOptions().SetDurationStyle(DurationStyleISO))
+	assert.NoError(t, err)
+	assert.Equal(t, "PT0S", v)
+
+	v, err = ToStringE(d, *NewOptions().SetDurationStyle(DurationStyleNumber).SetDurationUnit(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, "5400.5", v)
+
+	v, err = ToStringE(d, *NewOptions().SetDurationStyle(DurationStyleNumber))
+	assert.NoError(t, err)
+	assert.Equal(t, "5400500000000", v)
+}
+
+func TestDecodeDuration(t *testing.T) {
+	type config struct {
+		Timeout  time.Duration   `json:"timeout"`
+		Interval *time.Duration  `json:"interval"`
+		Retries  []time.Duration `json:"retries"`
+		Attempts int64           `json:"attempts"`
+	}
+	interval := 7 * 24 * time.Hour
+
+	var out config
+	op := NewOptions().SetDurationUnit(time.Second)
+	err := ToAny(`{"timeout": "1m30s", "interval": "1w", "retries": [1, "PT2S"], "attempts": 3}`, &out, *op)
+	assert.NoError(t, err)
+	assert.Equal(t, config{Timeout: 90 * time.Second, Interval: &interval, Retries: []time.Duration{time.Second, 2 * time.Second}, Attempts: 3}, out)
+
+	var m map[string]string
+	err = ToAny(out, &m, *NewOptions().SetDurationStyle(DurationStyleISO))
+	assert.NoError(t, err)
+	assert.Equal(t, "PT1M30S", m["timeout"])
+	assert.Equal(t, "PT168H", m["interval"])
+}
@@ -0,0 +1,167 @@
+package goany
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MapEntry is a key and value pair of a map, a map converts to a []MapEntry with SetMapEntries
+// so that its entries keep an order in formats without object keys.
+type MapEntry[K comparable, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+// entryFields returns the key and value field names of map entries, "key" and "value" if none is set.
+func (op *Options) entryFields() (string, string) {
+	key, value := op.mapEntryKey, op.mapEntryValue
+	if key == "" {
+		key = "key"
+	}
+	if value == "" {
+		value = "value"
+	}
+	return key, value
+}
+
+// decodeEntry decodes a map key and value into an entry output value. Struct entries get the key and
+// value in the fields named by the options, other entries are decoded from a map holding the two names.
+func (cli *anyClient) decodeEntry(key, value interface{}, outVal reflect.Value) error {
+	keyName, valueName := cli.options.entryFields()
+	if outVal.Kind() == reflect.Ptr {
+		if outVal.IsNil() {
+			outVal.Set(reflect.New(outVal.Type().Elem()))
+		}
+		return cli.decodeEntry(key, value, outVal.Elem())
+	}
+	if outVal.Kind() != reflect.Struct {
+		return cli.decodeAny(map[string]interface{}{keyName: key, valueName: value}, outVal)
+	}
+
+	basicOutVal := reflect.New(outVal.Type()).Elem()
+	keyField, ok := cli.entryField(basicOutVal, keyName)
+	if !ok {
+		return errors.Errorf(ErrFieldNoFound, keyName)
+	}
+	valueField, ok := cli.entryField(basicOutVal, valueName)
+	if !ok {
+		return errors.Errorf(ErrFieldNoFound, valueName)
+	}
+	if err := cli.decodeAny(key, keyField); err != nil {
+		return err
+	}
+	if err := cli.decodeAny(value, valueField); err != nil {
+		return err
+	}
+	outVal.Set(basicOutVal)
+	return nil
+}
+
+// structField returns the field of a struct value named name, named as mapToStruct names it by
+// GetFieldNameByTag. Ignored fields and, unless they are exported by the options, unexported fields
+// are not found. The fields of untagged embedded structs are promoted, a nil embedded pointer is
+// allocated on the way if alloc is set and skipped otherwise.
+func (cli *anyClient) structField(structVal reflect.Value, name string, alloc bool) (reflect.Value, bool) {
+	var embedded []reflect.Value
+	for i := 0; i < structVal.NumField(); i++ {
+		field := fieldInfo{fieldStruct: structVal.Type().Field(i), fieldVal: structVal.Field(i)}
+		field.fieldName = GetFieldNameByTag(field.fieldStruct, cli.options.tagName)
+		if !field.canUse(*cli.options) {
+			continue
+		}
+		if field.fieldStruct.PkgPath != "" {
+			if !field.fieldVal.CanAddr() {
+				continue
+			}
+			field.fieldVal = unexportedField(field.fieldVal)
+		}
+		if field.fieldName == name {
+			return field.fieldVal, true
+		}
+		if field.fieldStruct.Anonymous && field.fieldStruct.Tag.Get(cli.options.tagName) == "" {
+			embedded = append(embedded, field.fieldVal)
+		}
+	}
+	// fields of the struct hide the fields promoted from embedded structs
+	for _, embed := range embedded {
+		if embed.Kind() == reflect.Ptr {
+			if embed.IsNil() {
+				if !alloc || !embed.CanSet() || embed.Type().Elem().Kind() != reflect.Struct {
+					continue
+				}
+				// allocate only if the field is found in the embedded struct
+				if _, ok := cli.structField(reflect.New(embed.Type().Elem()).Elem(), name, alloc); !ok {
+					continue
+				}
+				embed.Set(reflect.New(embed.Type().Elem()))
+			}
+			embed = embed.Elem()
+		}
+		if embed.Kind() != reflect.Struct {
+			continue
+		}
+		if field, ok := cli.structField(embed, name, alloc); ok {
+			return field, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// entryField returns the key or value field of a struct entry named name, see structField.
+// Untagged fields are also found by their field name ignoring case, such as Key for "key".
+func (cli *anyClient) entryField(structVal reflect.Value, name string) (reflect.Value, bool) {
+	if field, ok := cli.structField(structVal, name, true); ok {
+		return field, true
+	}
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Type().Field(i)
+		if field.PkgPath == "" && field.Tag.Get(cli.options.tagName) == "" && strings.EqualFold(field.Name, name) {
+			return structVal.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// elementField returns the value named name in a list element, the field of a struct element
+// or the value of a map element. The name may be a dotted or JSON pointer path to a nested value.
+func (cli *anyClient) elementField(elemVal reflect.Value, name string) (reflect.Value, bool) {
+	if v, ok := cli.lookupPath(elemVal, []string{name}); ok {
+		return v, true
+	}
+	if isPath(name) {
+		return cli.lookupPath(elemVal, splitPath(name))
+	}
+	return reflect.Value{}, false
+}
+
+// entriesToMap converts a list of map entries into a map output value, the entries are structs
+// or maps holding a key and a value under the names of the options.
+func (cli *anyClient) entriesToMap(inValue reflect.Value, outVal reflect.Value) error {
+	basicOutVal := reflect.MakeMap(outVal.Type())
+	keyName, valueName := cli.options.entryFields()
+
+	for i := 0; i < inValue.Len(); i++ {
+		keyVal, ok := cli.elementField(inValue.Index(i), keyName)
+		if !ok {
+			return errors.Errorf(ErrFieldNoFound, keyName)
+		}
+		valueVal, ok := cli.elementField(inValue.Index(i), valueName)
+		if !ok {
+			return errors.Errorf(ErrFieldNoFound, valueName)
+		}
+
+		currentKey := reflect.Indirect(reflect.New(basicOutVal.Type().Key()))
+		currentValue := reflect.Indirect(reflect.New(basicOutVal.Type().Elem()))
+		if err := cli.decodeAny(keyVal.Interface(), currentKey); err != nil {
+			return err
+		}
+		if err := cli.decodeAny(valueVal.Interface(), currentValue); err != nil {
+			return err
+		}
+		basicOutVal.SetMapIndex(currentKey, currentValue)
+	}
+	outVal.Set(basicOutVal)
+	return nil
+}
@@ -0,0 +1,86 @@
+package goany
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapEntries(t *testing.T) {
+	in := map[string]int{"b": 2, "a": 1, "c": 3}
+	op := NewOptions().SetMapEntries(true).SetMapKeyOrder(MapOrderNatural)
+
+	t.Run("Test with map entry", func(t *testing.T) {
+		var out []MapEntry[string, int]
+		err := ToAny(in, &out, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, []MapEntry[string, int]{{"a", 1}, {"b", 2}, {"c", 3}}, out)
+
+		var back map[string]int
+		err = ToAny(out, &back, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, in, back)
+	})
+
+	t.Run("Test with converted types", func(t *testing.T) {
+		var out []*MapEntry[int, string]
+		err := ToAny(map[string]int{"2": 20, "1": 10}, &out, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, []*MapEntry[int, string]{{1, "10"}, {2, "20"}}, out)
+	})
+
+	t.Run("Test with user struct", func(t *testing.T) {
+		type header struct {
+			Name  string `json:"name"`
+			Value string `json:"val"`
+			Note  string `json:"note"`
+		}
+		op := NewOptions().SetMapEntries(true).SetMapKeyOrder(MapOrderNatural).SetMapEntryFields("name", "val")
+		var out []header
+		err := ToAny(map[string]string{"Accept": "*/*", "Host": "example.com"}, &out, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, []header{{Name: "Accept", Value: "*/*"}, {Name: "Host", Value: "example.com"}}, out)
+
+		var back map[string]string
+		err = ToAny(out, &back, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"Accept": "*/*", "Host": "example.com"}, back)
+	})
+
+	t.Run("Test with field name without tag", func(t *testing.T) {
+		type pair struct {
+			Key   string
+			Value int
+		}
+		var out []pair
+		err := ToAny(in, &out, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, []pair{{"a", 1}, {"b", 2}, {"c", 3}}, out)
+	})
+
+	t.Run("Test with interface and map entries", func(t *testing.T) {
+		var out []interface{}
+		err := ToAny(map[string]int{"a": 1}, &out, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{map[string]interface{}{"key": "a", "value": 1}}, out)
+
+		var back map[string]int
+		err = ToAny(`[{"key": "x", "value": 1}, {"key": "y", "value": "2"}]`, &back, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int{"x": 1, "y": 2}, back)
+	})
+
+	t.Run("Test with missing field", func(t *testing.T) {
+		type named struct {
+			Name string `json:"name"`
+		}
+		var out []named
+		err := ToAny(in, &out, *op)
+		assert.Equal(t, errors.Errorf(ErrFieldNoFound, "key").Error(), err.Error())
+
+		var back map[string]int
+		err = ToAny([]map[string]interface{}{{"key": "a"}}, &back, *op)
+		assert.Equal(t, errors.Errorf(ErrFieldNoFound, "value").Error(), err.Error())
+	})
+}
@@ -0,0 +1,231 @@
+package goany
+
+import (
+	"encoding"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// flatSegment is a part of a flat key, a map key or struct field name, or a list index.
+type flatSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// Flatten converts nested maps, structs and lists into a map of flat keys, such as
+// {"db.host": "localhost", "servers[0].port": 8080} with sep ".". Struct fields are named by their tags,
+// and times and types with a text form are kept as values. Map keys holding sep or brackets are ambiguous.
+func Flatten(in interface{}, sep string, op ...Options) (map[string]interface{}, error) {
+	cli := newAnyClient(op...)
+	out := make(map[string]interface{})
+	inVal, _ := indirectValue(reflect.ValueOf(in))
+	if inVal.Kind() != reflect.Map && inVal.Kind() != reflect.Struct {
+		return nil, errors.Errorf(ErrInToOut, in, "flat map")
+	}
+	if err := cli.flatten("", inVal, sep, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Unflatten converts a map of flat keys back into nested maps and lists, see Flatten.
+// Keys that need a value and a container at the same place are an error. Lists may have gaps,
+// but together they hold at most one element per flat key, so "a[50000000]" alone is an error.
+func Unflatten(in interface{}, sep string) (map[string]interface{}, error) {
+	inVal, _ := indirectValue(reflect.ValueOf(in))
+	if inVal.Kind() != reflect.Map || inVal.Type().Key().Kind() != reflect.String {
+		return nil, errors.Errorf(ErrInToOut, in, "nested map")
+	}
+	keys := inVal.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+	var root interface{} = map[string]interface{}{}
+	slots := len(keys)
+	for _, k := range keys {
+		var err error
+		if root, err = setFlat(root, splitFlatKey(k.String(), sep), inVal.MapIndex(k).Interface(), k.String(), &slots); err != nil {
+			return nil, err
+		}
+	}
+	return root.(map[string]interface{}), nil
+}
+
+// flatten adds the leaves of val to out under keys starting with prefix.
+func (cli *anyClient) flatten(prefix string, val reflect.Value, sep string, out map[string]interface{}) error {
+	for (val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface) && !val.IsNil() {
+		val = val.Elem()
+	}
+	switch val.Kind() {
+	case reflect.Map:
+		if val.Len() == 0 {
+			break
+		}
+		for _, k := range cli.options.mapKeys(val) {
+			key, err := toStringE(k.Interface(), *cli.options)
+			if err != nil {
+				return err
+			}
+			if err := cli.flatten(joinFlatKey(prefix, key, sep), val.MapIndex(k), sep, out); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Struct:
+		if isFlatLeaf(val) {
+			break
+		}
+		for i := 0; i < val.NumField(); i++ {
+			field := fieldInfo{fieldStruct: val.Type().Field(i), fieldVal: val.Field(i)}
+			field.fieldName = GetFieldNameByTag(field.fieldStruct, cli.options.tagName)
+			if !field.canUse(*cli.options) {
+				continue
+			}
+			if field.fieldStruct.PkgPath != "" {
+				if !field.fieldVal.CanAddr() {
+					return ErrInNotPtr
+				}
+				field.fieldVal = getUnexportedField(field.fieldVal)
+			}
+			key := joinFlatKey(prefix, field.fieldName, sep)
+			if field.fieldStruct.Anonymous && field.fieldStruct.Tag.Get(cli.options.tagName) == "" {
+				key = prefix // embedded fields are promoted
+			}
+			if err := cli.flatten(key, field.fieldVal, sep, out); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice, reflect.Array:
+		if val.Len() == 0 || val.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		for i := 0; i < val.Len(); i++ {
+			if err := cli.flatten(prefix+"["+strconv.Itoa(i)+"]", val.Index(i), sep, out); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var v interface{}
+	if val.IsValid() {
+		v = val.Interface()
+		if exported, ok := exportTime(v, *cli.options); ok {
+			v = exported
+		}
+	}
+	out[prefix] = v
+	return nil
+}
+
+// isFlatLeaf reports whether a struct is kept as a value by Flatten, that is a time or a type with a text form.
+func isFlatLeaf(val reflect.Value) bool {
+	if val.Type().Implements(textMarshalerType) || reflect.PtrTo(val.Type()).Implements(textMarshalerType) {
+		return true
+	}
+	_, ok := asTime(val.Interface(), nil)
+	return ok
+}
+
+// joinFlatKey appends a key to a flat key prefix.
+func joinFlatKey(prefix, key, sep string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + sep + key
+}
+
+// splitFlatKey splits a flat key into its segments, "servers[0].port" is servers, 0 and port.
+func splitFlatKey(key, sep string) []flatSegment {
+	var segments []flatSegment
+	parts := []string{key}
+	if sep != "" {
+		parts = strings.Split(key, sep)
+	}
+	for _, part := range parts {
+		name, indexes := part, []int(nil)
+		for strings.HasSuffix(name, "]") {
+			open := strings.LastIndexByte(name, '[')
+			if open < 0 {
+				break
+			}
+			i, err := strconv.Atoi(name[open+1 : len(name)-1])
+			if err != nil || i < 0 {
+				break
+			}
+			indexes = append([]int{i}, indexes...)
+			name = name[:open]
+		}
+		if name != "" || len(indexes) == 0 {
+			segments = append(segments, flatSegment{key: name})
+		}
+		for _, i := range indexes {
+			segments = append(segments, flatSegment{index: i, isIndex: true})
+		}
+	}
+	return segments
+}
+
+// setFlat sets value at the segments below node and returns the node, creating maps and lists on the way.
+// slots is the number of list elements that may still be created, growing a list beyond it is an error.
+func setFlat(node interface{}, segments []flatSegment, value interface{}, key string, slots *int) (interface{}, error) {
+	if len(segments) == 0 {
+		if node != nil {
+			return nil, errors.Errorf(ErrFlatKeyConflict, key)
+		}
+		return value, nil
+	}
+	segment := segments[0]
+	if segment.isIndex {
+		list, ok := node.([]interface{})
+		if node != nil && !ok {
+			return nil, errors.Errorf(ErrFlatKeyConflict, key)
+		}
+		if grow := segment.index + 1 - len(list); grow > 0 {
+			if grow > *slots {
+				return nil, errors.Errorf(ErrFlatIndex, key, segment.index)
+			}
+			*slots -= grow
+			list = append(list, make([]interface{}, grow)...)
+		}
+		child, err := setFlat(list[segment.index], segments[1:], value, key, slots)
+		if err != nil {
+			return nil, err
+		}
+		list[segment.index] = child
+		return list, nil
+	}
+	m, ok := node.(map[string]interface{})
+	if node != nil && !ok {
+		return nil, errors.Errorf(ErrFlatKeyConflict, key)
+	}
+	if m == nil {
+		m = make(map[string]interface{})
+	}
+	child, err := setFlat(m[segment.key], segments[1:], value, key, slots)
+	if err != nil {
+		return nil, err
+	}
+	m[segment.key] = child
+	return m, nil
+}
+
+// hasFlatKey reports whether a map with string keys holds a flat key with sep or a list index.
+func hasFlatKey(mapVal reflect.Value, sep string) bool {
+	if mapVal.Type().Key().Kind() != reflect.String {
+		return false
+	}
+	for _, k := range mapVal.MapKeys() {
+		if (sep != "" && strings.Contains(k.String(), sep)) || strings.HasSuffix(k.String(), "]") {
+			return true
+		}
+	}
+	return false
+}
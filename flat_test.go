@@ -0,0 +1,178 @@
+package goany
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type flatServer struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+}
+
+type FlatBase struct {
+	Env string `json:"env"`
+}
+
+type flatConfig struct {
+	FlatBase
+	Name    string            `json:"name"`
+	DB      flatServer        `json:"db"`
+	Servers []flatServer      `json:"servers"`
+	Labels  map[string]string `json:"labels"`
+	Backup  *flatServer       `json:"backup"`
+	Since   time.Time         `json:"since"`
+	Tags    []string          `json:"tags"`
+}
+
+func TestFlatten(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config := flatConfig{
+		FlatBase: FlatBase{Env: "prod"},
+		Name:     "app",
+		DB:       flatServer{Host: "db", Port: 5432},
+		Servers:  []flatServer{{Host: "a", Port: 80}, {Host: "b", Port: 81}},
+		Labels:   map[string]string{"team": "core"},
+		Since:    since,
+		Tags:     []string{},
+	}
+	expected := map[string]interface{}{
+		"env":             "prod",
+		"name":            "app",
+		"db.host":         "db",
+		"db.port":         5432,
+		"servers[0].host": "a",
+		"servers[0].port": 80,
+		"servers[1].host": "b",
+		"servers[1].port": 81,
+		"labels.team":     "core",
+		"backup":          (*flatServer)(nil),
+		"since":           since,
+		"tags":            []string{},
+	}
+
+	t.Run("Test with struct", func(t *testing.T) {
+		out, err := Flatten(config, ".")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, out)
+	})
+
+	t.Run("Test with nested map and separator", func(t *testing.T) {
+		in := map[string]interface{}{"a": map[string]interface{}{"b": []interface{}{1, map[string]int{"c": 2}}}}
+		out, err := Flatten(&in, "/")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"a/b[0]": 1, "a/b[1]/c": 2}, out)
+	})
+
+	t.Run("Test with invalid input", func(t *testing.T) {
+		_, err := Flatten([]int{1}, ".")
+		assert.Error(t, err)
+	})
+
+	t.Run("Test with options", func(t *testing.T) {
+		var out map[string]interface{}
+		op := NewOptions().SetFlatKeys(true).SetTimeExport(TimeExportUnix)
+		err := ToAny(config, &out, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, 5432, out["db.port"])
+		assert.Equal(t, "b", out["servers[1].host"])
+		assert.Equal(t, since.Unix(), out["since"])
+
+		var strs map[string]string
+		err = ToAny(config, &strs, *NewOptions().SetFlatKeys(true).SetFlatKeySeparator("__"))
+		assert.NoError(t, err)
+		assert.Equal(t, "5432", strs["db__port"])
+		assert.Equal(t, "81", strs["servers[1]__port"])
+	})
+}
+
+func TestUnflatten(t *testing.T) {
+	t.Run("Test with keys", func(t *testing.T) {
+		in := map[string]interface{}{
+			"db.host":         "db",
+			"db.port":         "5432",
+			"servers[1].port": 81,
+			"servers[0].host": "a",
+			"matrix[0][1]":    1,
+			"name":            "app",
+		}
+		out, err := Unflatten(in, ".")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"db":      map[string]interface{}{"host": "db", "port": "5432"},
+			"servers": []interface{}{map[string]interface{}{"host": "a"}, map[string]interface{}{"port": 81}},
+			"matrix":  []interface{}{[]interface{}{nil, 1}},
+			"name":    "app",
+		}, out)
+	})
+
+	t.Run("Test with conflict", func(t *testing.T) {
+		_, err := Unflatten(map[string]interface{}{"a": 1, "a.b": 2}, ".")
+		assert.Equal(t, errors.Errorf(ErrFlatKeyConflict, "a.b").Error(), err.Error())
+
+		_, err = Unflatten(map[string]string{"a[0]": "x", "a.b": "y"}, ".")
+		assert.Error(t, err)
+
+		_, err = Unflatten(map[int]string{1: "x"}, ".")
+		assert.Error(t, err)
+	})
+
+	t.Run("Test with list index beyond the keys", func(t *testing.T) {
+		_, err := Unflatten(map[string]interface{}{"a[50000000]": 1}, ".")
+		assert.Equal(t, errors.Errorf(ErrFlatIndex, "a[50000000]", 50000000).Error(), err.Error())
+
+		_, err = Unflatten(map[string]interface{}{"a[0][2]": 1, "b": 2}, ".")
+		assert.Error(t, err)
+
+		nested, err := Unflatten(map[string]interface{}{"a[2]": 1, "b": 2, "c": 3}, ".")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"a": []interface{}{nil, nil, 1}, "b": 2, "c": 3}, nested)
+
+		var out flatConfig
+		err = ToAny(map[string]string{"servers[99999999].host": "a"}, &out, *NewOptions().SetFlatKeys(true))
+		assert.Equal(t, errors.Errorf(ErrFlatIndex, "servers[99999999].host", 99999999).Error(), err.Error())
+	})
+
+	t.Run("Test with round trip", func(t *testing.T) {
+		in := map[string]interface{}{"a": map[string]interface{}{"b": []interface{}{"x", "y"}}, "c": 1}
+		flat, err := Flatten(in, ".")
+		assert.NoError(t, err)
+		out, err := Unflatten(flat, ".")
+		assert.NoError(t, err)
+		assert.Equal(t, in, out)
+	})
+
+	t.Run("Test with options to struct", func(t *testing.T) {
+		in := map[string]string{
+			"env":             "dev",
+			"name":            "app",
+			"db.host":         "db",
+			"db.port":         "5432",
+			"servers[0].host": "a",
+			"servers[1].port": "81",
+			"labels.team":     "core",
+			"backup.host":     "bk",
+			"since":           "2024-01-01",
+		}
+		var out flatConfig
+		err := ToAny(in, &out, *NewOptions().SetFlatKeys(true))
+		assert.NoError(t, err)
+		assert.Equal(t, flatConfig{
+			FlatBase: FlatBase{Env: "dev"},
+			Name:     "app",
+			DB:       flatServer{Host: "db", Port: 5432},
+			Servers:  []flatServer{{Host: "a"}, {Port: 81}},
+			Labels:   map[string]string{"team": "core"},
+			Backup:   &flatServer{Host: "bk"},
+			Since:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		}, out)
+
+		var plain flatConfig
+		err = ToAny(in, &plain)
+		assert.NoError(t, err)
+		assert.Equal(t, flatServer{}, plain.DB)
+	})
+}
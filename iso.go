@@ -0,0 +1,280 @@
+package goany
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// isoTimeLayouts are the layouts of the time part of ISO-8601 week, ordinal and basic dates.
+var isoTimeLayouts = []string{
+	"15:04:05.999999999Z07:00",
+	"15:04:05.999999999",
+	"15:04Z07:00",
+	"15:04",
+	"150405.999999999Z0700",
+	"150405.999999999Z07:00",
+	"150405.999999999",
+	"1504Z0700",
+	"1504",
+}
+
+// TimeRange is a time interval, it converts from ISO-8601 intervals such as "2024-01-01/2024-02-01",
+// "2024-01-01/P1M" and "P1D/2024-01-02", and from maps with the keys start and end.
+type TimeRange struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Duration returns the length of the range.
+func (r TimeRange) Duration() time.Duration {
+	return r.End.Sub(r.Start)
+}
+
+// Contains reports whether t is in the range, the start is included and the end is excluded.
+func (r TimeRange) Contains(t time.Time) bool {
+	return !t.Before(r.Start) && t.Before(r.End)
+}
+
+// String returns the range as an ISO-8601 interval of RFC3339 times.
+func (r TimeRange) String() string {
+	return r.Start.Format(time.RFC3339Nano) + "/" + r.End.Format(time.RFC3339Nano)
+}
+
+// ToTimeRange attempts to convert an interface value to a TimeRange value
+func ToTimeRange(v interface{}, op ...Options) TimeRange {
+	r, _ := ToTimeRangeE(v, op...)
+	return r
+}
+
+// ToTimeRangeE attempts to convert an interface value to a TimeRange value.
+func ToTimeRangeE(v interface{}, op ...Options) (TimeRange, error) {
+	var out TimeRange
+	err := ToAny(v, &out, op...)
+	return out, err
+}
+
+// decodeTimeRange decodes an input value into a TimeRange output value. Strings are parsed as
+// ISO-8601 intervals, maps and json objects are decoded field by field.
+func (cli *anyClient) decodeTimeRange(in interface{}, outVal reflect.Value) error {
+	if isObjectInput(in) {
+		return cli.decodeStruct(in, outVal)
+	}
+	switch v := Indirect(in).(type) {
+	case TimeRange:
+		outVal.Set(reflect.ValueOf(v))
+		return nil
+	case string:
+		r, err := parseTimeRange(v, *cli.options)
+		if err != nil {
+			return err
+		}
+		outVal.Set(reflect.ValueOf(r))
+		return nil
+	default:
+		return errors.Errorf(ErrInToOut, in, "time range")
+	}
+}
+
+// parseTimeRange parses an ISO-8601 interval made of a start and an end, a start and a period,
+// or a period and an end. Periods may hold years and months, they follow the calendar.
+func parseTimeRange(v string, op Options) (TimeRange, error) {
+	location := op.location
+	if location == nil {
+		location = time.UTC
+	}
+	startStr, endStr, ok := strings.Cut(strings.TrimSpace(v), "/")
+	if !ok {
+		return TimeRange{}, errors.Errorf(ErrInToOut, v, "time range")
+	}
+	startIsPeriod := strings.HasPrefix(startStr, "P")
+	endIsPeriod := strings.HasPrefix(endStr, "P")
+	var r TimeRange
+	var err error
+	switch {
+	case startIsPeriod && endIsPeriod:
+		return TimeRange{}, errors.Errorf(ErrInToOut, v, "time range")
+	case endIsPeriod:
+		if r.Start, err = stringToTime(startStr, location, op); err != nil {
+			return TimeRange{}, err
+		}
+		r.End, err = addISOPeriod(r.Start, endStr, 1)
+	case startIsPeriod:
+		if r.End, err = stringToTime(endStr, location, op); err != nil {
+			return TimeRange{}, err
+		}
+		r.Start, err = addISOPeriod(r.End, startStr, -1)
+	default:
+		if r.Start, err = stringToTime(startStr, location, op); err != nil {
+			return TimeRange{}, err
+		}
+		r.End, err = stringToTime(endStr, location, op)
+	}
+	if err != nil {
+		return TimeRange{}, err
+	}
+	if r.End.Before(r.Start) {
+		return TimeRange{}, errors.Errorf(ErrInToOut, v, "time range")
+	}
+	return r, nil
+}
+
+// addISOPeriod adds an ISO-8601 period such as "P1Y2M10DT2H30M" to t, multiplied by sign.
+// Years, months, weeks and days are added on the calendar, the time part as a fixed duration.
+func addISOPeriod(t time.Time, period string, sign int) (time.Time, error) {
+	datePart, timePart, hasTime := strings.Cut(strings.TrimPrefix(period, "P"), "T")
+	if datePart == "" && !hasTime {
+		return time.Time{}, errors.Errorf(ErrUnableConvertDuration, period)
+	}
+	var years, months, days int
+	for datePart != "" {
+		i := strings.IndexFunc(datePart, func(r rune) bool { return r < '0' || r > '9' })
+		if i <= 0 {
+			return time.Time{}, errors.Errorf(ErrUnableConvertDuration, period)
+		}
+		n, err := strconv.Atoi(datePart[:i])
+		if err != nil {
+			return time.Time{}, errors.Errorf(ErrUnableConvertDuration, period)
+		}
+		switch datePart[i] {
+		case 'Y':
+			years += n
+		case 'M':
+			months += n
+		case 'W':
+			days += 7 * n
+		case 'D':
+			days += n
+		default:
+			return time.Time{}, errors.Errorf(ErrUnableConvertDuration, period)
+		}
+		datePart = datePart[i+1:]
+	}
+	var d time.Duration
+	if hasTime {
+		var err error
+		if d, err = parseISODuration("PT" + timePart); err != nil {
+			return time.Time{}, errors.Errorf(ErrUnableConvertDuration, period)
+		}
+	}
+	return t.AddDate(sign*years, sign*months, sign*days).Add(time.Duration(sign) * d), nil
+}
+
+// parseISOTime parses the ISO-8601 forms the layouts cannot express: week dates ("2024-W05-3"),
+// ordinal dates ("2024-032") and the basic format without separators ("20240101T120000Z").
+// A time may follow the date after "T". The form of the date is returned as TimeFormatISOWeek,
+// TimeFormatISOOrdinal or TimeFormatISOBasic.
+func parseISOTime(v string, location *time.Location) (time.Time, string, bool) {
+	datePart, timePart, hasTime := strings.Cut(strings.TrimSpace(v), "T")
+	year, month, day, form, ok := parseISODate(datePart)
+	if !ok {
+		return time.Time{}, "", false
+	}
+	if !hasTime {
+		return time.Date(year, month, day, 0, 0, 0, 0, location), form, true
+	}
+	for _, layout := range isoTimeLayouts {
+		if t, err := time.ParseInLocation(layout, timePart, location); err == nil {
+			return time.Date(year, month, day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location()), form, true
+		}
+	}
+	return time.Time{}, "", false
+}
+
+// parseISODate parses the date part of parseISOTime, in extended or basic format, and returns its form.
+func parseISODate(s string) (int, time.Month, int, string, bool) {
+	if len(s) < 7 || !isDigits(s[:4]) {
+		return 0, 0, 0, "", false
+	}
+	year, _ := strconv.Atoi(s[:4])
+	rest := strings.TrimPrefix(s[4:], "-")
+	switch {
+	case strings.HasPrefix(rest, "W"):
+		// week date: Www-D or WwwD, the day defaults to Monday
+		rest = rest[1:]
+		if len(rest) < 2 || !isDigits(rest[:2]) {
+			return 0, 0, 0, "", false
+		}
+		week, _ := strconv.Atoi(rest[:2])
+		weekday := 1
+		if rest = strings.TrimPrefix(rest[2:], "-"); rest != "" {
+			if len(rest) != 1 || rest[0] < '1' || rest[0] > '7' {
+				return 0, 0, 0, "", false
+			}
+			weekday = int(rest[0] - '0')
+		}
+		t, ok := isoWeekDate(year, week, weekday)
+		if !ok {
+			return 0, 0, 0, "", false
+		}
+		return t.Year(), t.Month(), t.Day(), TimeFormatISOWeek, true
+	case len(rest) == 3 && isDigits(rest):
+		// ordinal date: DDD
+		yearDay, _ := strconv.Atoi(rest)
+		t := time.Date(year, 1, yearDay, 0, 0, 0, 0, time.UTC)
+		if yearDay < 1 || t.Year() != year {
+			return 0, 0, 0, "", false
+		}
+		return year, t.Month(), t.Day(), TimeFormatISOOrdinal, true
+	case len(s) == 8 && isDigits(s):
+		// basic calendar date: YYYYMMDD
+		t, err := time.Parse("20060102", s)
+		if err != nil {
+			return 0, 0, 0, "", false
+		}
+		return t.Year(), t.Month(), t.Day(), TimeFormatISOBasic, true
+	}
+	return 0, 0, 0, "", false
+}
+
+// isoWeekDate returns the date of the ISO-8601 week date, week 1 is the week with January 4.
+func isoWeekDate(year, week, weekday int) (time.Time, bool) {
+	if week < 1 || week > 53 {
+		return time.Time{}, false
+	}
+	jan4 := time.Date(year, 1, 4, 0, 0, 0, 0, time.UTC)
+	monday := jan4.AddDate(0, 0, 1-isoWeekday(jan4))
+	t := monday.AddDate(0, 0, (week-1)*7+weekday-1)
+	if y, w := t.ISOWeek(); y != year || w != week {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// isoWeekday returns the ISO-8601 day of the week, Monday is 1 and Sunday is 7.
+func isoWeekday(t time.Time) int {
+	if t.Weekday() == time.Sunday {
+		return 7
+	}
+	return int(t.Weekday())
+}
+
+// isDigits reports whether s is made of ASCII digits only.
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// formatTime renders t in the time format of op, which may be a layout or one of
+// TimeFormatUnix, TimeFormatISOWeek and TimeFormatISOOrdinal.
+func formatTime(t time.Time, op Options) string {
+	switch op.timeFormat {
+	case TimeFormatUnix:
+		return strconv.FormatInt(timeToUnix(t, op.timeUnit), 10)
+	case TimeFormatISOWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d-%d", year, week, isoWeekday(t))
+	case TimeFormatISOOrdinal:
+		return fmt.Sprintf("%04d-%03d", t.Year(), t.YearDay())
+	default:
+		return t.Format(op.timeFormat)
+	}
+}
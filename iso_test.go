@@ -0,0 +1,148 @@
+package goany
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestISOTime(t *testing.T) {
+	shanghai, _ := time.LoadLocation("Asia/Shanghai")
+
+	tests := []struct {
+		name     string
+		input    string
+		op       *Options
+		expected time.Time
+	}{
+		{name: "week date", input: "2024-W05-3", expected: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{name: "week date basic", input: "2024W053", expected: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{name: "week without day", input: "2024-W01", expected: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "week in previous year", input: "2021-W01-1", expected: time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{name: "week 53", input: "2020-W53-7", expected: time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{name: "week date with time", input: "2024-W05-3T10:30:00Z", expected: time.Date(2024, 1, 31, 10, 30, 0, 0, time.UTC)},
+		{name: "ordinal date", input: "2024-032", expected: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "ordinal leap day", input: "2024366", expected: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{name: "basic", input: "20240101T120000Z", expected: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)},
+		{name: "basic date", input: "20240101", expected: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "basic fraction", input: "20240101T120000.5", expected: time.Date(2024, 1, 1, 12, 0, 0, 500000000, time.UTC)},
+		{
+			name:     "basic offset",
+			input:    "20240101T1200+0800",
+			expected: time.Date(2024, 1, 1, 12, 0, 0, 0, time.FixedZone("", 8*3600)),
+		},
+		{
+			name:     "location",
+			input:    "2024-032T08:00",
+			op:       NewOptions().SetLocation(shanghai),
+			expected: time.Date(2024, 2, 1, 8, 0, 0, 0, shanghai),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op := tt.op
+			if op == nil {
+				op = NewOptions()
+			}
+			v, err := ToTimeE(tt.input, *op)
+			assert.NoError(t, err)
+			assert.True(t, tt.expected.Equal(v), v.String())
+		})
+	}
+
+	t.Run("Test with invalid", func(t *testing.T) {
+		for _, in := range []string{"2024-W54-1", "2021-W53-1", "2024-W05-8", "2023-366", "2024-000", "20241301", "2024-W05-3T25:00"} {
+			_, err := ToTimeE(in)
+			assert.Error(t, err, in)
+		}
+	})
+
+	t.Run("Test with strict", func(t *testing.T) {
+		_, err := ToTimeE("2024-W05-3", *NewOptions().SetTimeStrict(true))
+		assert.Error(t, err)
+
+		op := NewOptions().SetTimeStrict(true).SetTimeFormat(TimeFormatISOWeek)
+		v, err := ToTimeE("2024-W05-3", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), v)
+
+		for _, in := range []string{"2024-031", "20240131", "2024-01-31", "now"} {
+			_, err = ToTimeE(in, *op.SetRelativeTime(true))
+			assert.Error(t, err, in)
+		}
+
+		v, err = ToTimeE("2024-031", *NewOptions().SetTimeStrict(true).SetTimeFormat(TimeFormatISOOrdinal))
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), v)
+		_, err = ToTimeE("2024-W05-3", *NewOptions().SetTimeStrict(true).SetTimeFormat(TimeFormatISOOrdinal))
+		assert.Error(t, err)
+	})
+
+	t.Run("Test with format", func(t *testing.T) {
+		in := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+		assert.Equal(t, "2024-W05-3", formatAs(in, TimeFormatISOWeek))
+		assert.Equal(t, "2024-031", formatAs(in, TimeFormatISOOrdinal))
+		assert.Equal(t, "20240131T120000Z", formatAs(in, TimeFormatISOBasic))
+		assert.Equal(t, "2020-W53-7", formatAs(time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC), TimeFormatISOWeek))
+	})
+}
+
+// formatAs converts v to a string in the given time format.
+func formatAs(v interface{}, format string) string {
+	s, _ := ToStringE(v, *NewOptions().SetTimeFormat(format))
+	return s
+}
+
+func TestTimeRange(t *testing.T) {
+	jan := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		input    interface{}
+		expected TimeRange
+	}{
+		{name: "start and end", input: "2024-01-01/2024-02-01", expected: TimeRange{Start: jan, End: feb}},
+		{name: "start and period", input: "2024-01-01/P1M", expected: TimeRange{Start: jan, End: feb}},
+		{name: "period and end", input: "P31D/2024-02-01", expected: TimeRange{Start: jan, End: feb}},
+		{
+			name:     "period with time",
+			input:    "2024-01-01T00:00:00Z/P1Y2M3DT4H30M",
+			expected: TimeRange{Start: jan, End: time.Date(2025, 3, 4, 4, 30, 0, 0, time.UTC)},
+		},
+		{name: "week dates", input: "2024-W01-1/2024-W01-7", expected: TimeRange{Start: jan, End: jan.AddDate(0, 0, 6)}},
+		{name: "map", input: map[string]interface{}{"start": "2024-01-01", "end": "2024-02-01"}, expected: TimeRange{Start: jan, End: feb}},
+		{name: "time range", input: &TimeRange{Start: jan, End: feb}, expected: TimeRange{Start: jan, End: feb}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := ToTimeRangeE(tt.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+
+	t.Run("Test with invalid", func(t *testing.T) {
+		for _, in := range []interface{}{"2024-01-01", "P1D/P2D", "2024-02-01/2024-01-01", "2024-01-01/P1X", "2024-01-01/x", 12} {
+			_, err := ToTimeRangeE(in)
+			assert.Error(t, err, in)
+		}
+	})
+
+	t.Run("Test with struct field and string", func(t *testing.T) {
+		type report struct {
+			Period TimeRange `json:"period"`
+		}
+		var out report
+		err := ToAny(`{"period": "2024-01-01/P1M"}`, &out)
+		assert.NoError(t, err)
+		assert.Equal(t, TimeRange{Start: jan, End: feb}, out.Period)
+		assert.Equal(t, 31*24*time.Hour, out.Period.Duration())
+		assert.True(t, out.Period.Contains(jan))
+		assert.False(t, out.Period.Contains(feb))
+
+		assert.Equal(t, "2024-01-01/2024-02-01", formatAs(out.Period, "2006-01-02"))
+		assert.Equal(t, "2024-01-01T00:00:00Z/2024-02-01T00:00:00Z", out.Period.String())
+	})
+}
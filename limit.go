@@ -0,0 +1,135 @@
+package goany
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// LimitError is returned when an input exceeds a resource limit of the options, see SetMaxInputSize,
+// SetMaxLength, SetMaxElements and SetMaxDepth.
+type LimitError struct {
+	Kind  int    // LimitInputSize, LimitLength, LimitElements or LimitDepth
+	Limit int    // the limit of the options
+	Size  int    // the size of the input that exceeds the limit
+	Path  string // the path of the value being decoded, such as "orders[2].items"
+}
+
+func (e *LimitError) Error() string {
+	switch e.Kind {
+	case LimitInputSize:
+		return fmt.Sprintf(ErrMaxInputSize, e.Size, e.Limit, e.Path)
+	case LimitLength:
+		return fmt.Sprintf(ErrMaxLength, e.Size, e.Limit, e.Path)
+	case LimitElements:
+		return fmt.Sprintf(ErrMaxElements, e.Limit, e.Path)
+	default:
+		return fmt.Sprintf(ErrMaxDepth, e.Limit, e.Path)
+	}
+}
+
+// limitError returns a LimitError at the path of the value being decoded.
+func (cli *anyClient) limitError(kind, limit, size int) error {
+	return &LimitError{Kind: kind, Limit: limit, Size: size, Path: cli.state.pathString()}
+}
+
+// checkInputSize checks the size of a json string input against the maxInputSize option.
+func (cli *anyClient) checkInputSize(size int) error {
+	if limit := cli.options.maxInputSize; limit > 0 && size > limit {
+		return cli.limitError(LimitInputSize, limit, size)
+	}
+	return nil
+}
+
+// checkLength checks the length of an input map, list or string against the maxLength option.
+// It is called before an output of that length is allocated.
+func (cli *anyClient) checkLength(length int) error {
+	if limit := cli.options.maxLength; limit > 0 && length > limit {
+		return cli.limitError(LimitLength, limit, length)
+	}
+	return nil
+}
+
+// countElement counts a decoded element against the maxElements option, and checks the depth of the
+// path against the maxDepth option.
+func (cli *anyClient) countElement() error {
+	state := cli.state
+	state.elements++
+	if limit := cli.options.maxElements; limit > 0 && state.elements > limit {
+		return cli.limitError(LimitElements, limit, state.elements)
+	}
+	if limit := cli.options.maxDepth; limit > 0 && len(state.path) > limit {
+		return cli.limitError(LimitDepth, limit, len(state.path))
+	}
+	return nil
+}
+
+// checkAssigned applies the length, element and depth limits to a value that is assigned to an
+// interface as it is, such as the maps and lists of a json string decoded into an interface{}.
+// The value is walked as the decoders would walk it, references already on the way are skipped.
+func (cli *anyClient) checkAssigned(val reflect.Value) error {
+	op := cli.options
+	if op.maxLength <= 0 && op.maxElements <= 0 && op.maxDepth <= 0 {
+		return nil
+	}
+	return cli.checkValue(val, make(map[copyRef]bool))
+}
+
+// checkValue checks the length of val and the elements below it, see checkAssigned.
+func (cli *anyClient) checkValue(val reflect.Value, visiting map[copyRef]bool) error {
+	for val.Kind() == reflect.Interface && !val.IsNil() {
+		val = val.Elem()
+	}
+	if ref, ok := refOf(val); ok {
+		if visiting[ref] {
+			return nil
+		}
+		visiting[ref] = true
+		defer delete(visiting, ref)
+	}
+	switch val.Kind() {
+	case reflect.Ptr:
+		if !val.IsNil() {
+			return cli.checkValue(val.Elem(), visiting)
+		}
+	case reflect.Map:
+		if err := cli.checkLength(val.Len()); err != nil {
+			return err
+		}
+		iter := val.MapRange()
+		for iter.Next() {
+			if err := cli.checkElement(keySegment(iter.Key()), iter.Value(), visiting); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if err := cli.checkLength(val.Len()); err != nil {
+			return err
+		}
+		for i := 0; i < val.Len(); i++ {
+			if err := cli.checkElement(indexSegment(i), val.Index(i), visiting); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < val.NumField(); i++ {
+			if field := val.Type().Field(i); field.PkgPath == "" {
+				segment := flatSegment{key: GetFieldNameByTag(field, cli.options.tagName)}
+				if err := cli.checkElement(segment, val.Field(i), visiting); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkElement counts an element at segment and checks it, see checkAssigned.
+func (cli *anyClient) checkElement(segment flatSegment, val reflect.Value, visiting map[copyRef]bool) error {
+	state := cli.state
+	state.path = append(state.path, segment)
+	defer func() { state.path = state.path[:len(state.path)-1] }()
+	if err := cli.countElement(); err != nil {
+		return err
+	}
+	return cli.checkValue(val, visiting)
+}
@@ -0,0 +1,108 @@
+package goany
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimits(t *testing.T) {
+	type item struct {
+		Sku  string   `json:"sku"`
+		Tags []string `json:"tags"`
+	}
+	type order struct {
+		Items []item `json:"items"`
+	}
+	in := `{"items":[{"sku":"a","tags":["x","y","z"]},{"sku":"b","tags":[]}]}`
+
+	tests := []struct {
+		name     string
+		op       *Options
+		expected *LimitError
+	}{
+		{name: "input size", op: NewOptions().SetMaxInputSize(32), expected: &LimitError{Kind: LimitInputSize, Limit: 32, Size: len(in)}},
+		{name: "length", op: NewOptions().SetMaxLength(2), expected: &LimitError{Kind: LimitLength, Limit: 2, Size: 3, Path: "items[0].tags"}},
+		{name: "depth", op: NewOptions().SetMaxDepth(3), expected: &LimitError{Kind: LimitDepth, Limit: 3, Size: 4, Path: "items[0].tags[0]"}},
+		{name: "within limits", op: NewOptions().SetMaxInputSize(len(in)).SetMaxLength(3).SetMaxElements(10).SetMaxDepth(4)},
+	}
+	for _, tt := range tests {
+		t.Run("Test with "+tt.name, func(t *testing.T) {
+			var out order
+			err := ToAny(in, &out, *tt.op)
+			if tt.expected == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, order{Items: []item{{Sku: "a", Tags: []string{"x", "y", "z"}}, {Sku: "b", Tags: []string{}}}}, out)
+				return
+			}
+			var limitErr *LimitError
+			assert.True(t, errors.As(err, &limitErr))
+			assert.Equal(t, tt.expected, limitErr)
+		})
+	}
+
+	t.Run("Test with elements", func(t *testing.T) {
+		var out [][]int
+		err := ToAny("[[1,2],[3,4]]", &out, *NewOptions().SetMaxElements(5))
+		var limitErr *LimitError
+		assert.True(t, errors.As(err, &limitErr))
+		assert.Equal(t, &LimitError{Kind: LimitElements, Limit: 5, Size: 6, Path: "[1][1]"}, limitErr)
+
+		err = ToAny("[[1,2],[3,4]]", &out, *NewOptions().SetMaxElements(6))
+		assert.NoError(t, err)
+		assert.Equal(t, [][]int{{1, 2}, {3, 4}}, out)
+	})
+
+	t.Run("Test with map and chars", func(t *testing.T) {
+		var out map[string]int
+		err := ToAny(map[string]int{"a": 1, "b": 2, "c": 3}, &out, *NewOptions().SetMaxLength(2))
+		assert.Equal(t, "length 3 exceeds the limit of 2 at \"\"", err.Error())
+
+		var list []string
+		err = ToAny(map[string]int{"a": 1, "b": 2, "c": 3}, &list, *NewOptions().SetMaxLength(2))
+		assert.Error(t, err)
+
+		var byIndex map[int]int
+		err = ToAny([]int{1, 2, 3}, &byIndex, *NewOptions().SetMaxLength(2))
+		assert.Error(t, err)
+
+		var chars []rune
+		err = ToAny("abc", &chars, *NewOptions().SetRuneAsChar(true).SetMaxLength(2))
+		assert.Error(t, err)
+	})
+
+	t.Run("Test with values assigned to interfaces", func(t *testing.T) {
+		nested := strings.Repeat(`{"a":`, 50) + "1" + strings.Repeat("}", 50)
+		var out map[string]interface{}
+		err := ToAny(nested, &out, *NewOptions().SetMaxDepth(10))
+		var limitErr *LimitError
+		assert.True(t, errors.As(err, &limitErr))
+		assert.Equal(t, LimitDepth, limitErr.Kind)
+		assert.Equal(t, "a.a.a.a.a.a.a.a.a.a.a", limitErr.Path)
+
+		list := "[" + strings.Repeat("1,", 10000) + "1]"
+		err = ToAny(`{"list":`+list+`}`, &out, *NewOptions().SetMaxLength(10000))
+		assert.True(t, errors.As(err, &limitErr))
+		assert.Equal(t, &LimitError{Kind: LimitLength, Limit: 10000, Size: 10001, Path: "list"}, limitErr)
+
+		err = ToAny(`{"list":`+list+`}`, &out, *NewOptions().SetMaxElements(10000))
+		assert.True(t, errors.As(err, &limitErr))
+		assert.Equal(t, &LimitError{Kind: LimitElements, Limit: 10000, Size: 10001, Path: "list[9999]"}, limitErr)
+
+		var v interface{}
+		err = ToAny([]interface{}{[]int{1, 2, 3}}, &v, *NewOptions().SetMaxLength(2))
+		assert.True(t, errors.As(err, &limitErr))
+		assert.Equal(t, &LimitError{Kind: LimitLength, Limit: 2, Size: 3, Path: "[0]"}, limitErr)
+
+		assert.NoError(t, ToAny(`{"list":`+list+`}`, &out, *NewOptions().SetMaxLength(10001).SetMaxElements(10002)))
+		assert.Len(t, out["list"], 10001)
+	})
+
+	t.Run("Test with error messages", func(t *testing.T) {
+		assert.Equal(t, `input of 10 bytes exceeds the limit of 8 bytes at ""`, (&LimitError{Kind: LimitInputSize, Limit: 8, Size: 10}).Error())
+		assert.Equal(t, `more than 5 elements at "a[1]"`, (&LimitError{Kind: LimitElements, Limit: 5, Size: 6, Path: "a[1]"}).Error())
+		assert.Equal(t, errors.Errorf(ErrMaxDepth, 2, "a.b").Error(), (&LimitError{Kind: LimitDepth, Limit: 2, Size: 3, Path: "a.b"}).Error())
+	})
+}
@@ -17,6 +17,12 @@ func (cli *anyClient) decodeList(in interface{}, outVal reflect.Value) error {
 		// Decode an input list into an output list element by element.
 		return cli.listToList(in, outVal)
 	case reflect.String:
+		// With rune/byte semantics, a string is split into a []rune or []byte.
+		if cli.options.runeAsChar && outVal.Kind() == reflect.Slice {
+			if elemKind := outVal.Type().Elem().Kind(); elemKind == reflect.Int32 || elemKind == reflect.Uint8 {
+				return cli.stringToChars(inVal.String(), outVal)
+			}
+		}
 		// If the input is a string, attempt to decode it as JSON into a list.
 		return cli.stringToAny(in, outVal)
 	case reflect.Ptr:
@@ -26,8 +32,10 @@ func (cli *anyClient) decodeList(in interface{}, outVal reflect.Value) error {
 	}
 }
 
-// mapToList converts a map input into a slice output value. If the mapKeyToList option is true,
-// the map's keys are used to populate the slice, otherwise the map's values are used.
+// mapToList converts a map input into a slice output value. If the mapEntries option is true, the slice
+// is populated with entries of key and value, if the mapKeyToList option is true, the map's keys are used
+// to populate the slice, otherwise the map's values are used.
+// The entries are visited in the map key order of the options.
 func (cli *anyClient) mapToList(in interface{}, outVal reflect.Value) error {
 	_, inVal := ReflectTypeValue(in)
 	var basicOutVal reflect.Value
@@ -37,19 +45,25 @@ func (cli *anyClient) mapToList(in interface{}, outVal reflect.Value) error {
 		basicOutVal = reflect.New(arrType).Elem()
 	} else {
 		// If the output is a slice, create a new slice of the appropriate type and size.
+		if err := cli.checkLength(inVal.Len()); err != nil {
+			return err
+		}
 		basicOutVal = reflect.MakeSlice(outVal.Type(), inVal.Len(), inVal.Len())
 	}
-	iter := inVal.MapRange()
-	i := 0
-	for iter.Next() {
-		v := iter.Value().Interface()
+	for i, k := range cli.options.mapKeys(inVal) {
+		v := inVal.MapIndex(k).Interface()
+		if cli.options.mapEntries {
+			if err := cli.decodeEntry(k.Interface(), v, basicOutVal.Index(i)); err != nil {
+				return err
+			}
+			continue
+		}
 		if cli.options.mapKeyToList {
-			v = iter.Key().Interface()
+			v = k.Interface()
 		}
-		if err := cli.decodeAny(v, basicOutVal.Index(i)); err != nil {
+		if err := cli.decodeAt(keySegment(k), v, basicOutVal.Index(i)); err != nil {
 			return err
 		}
-		i++
 	}
 	outVal.Set(basicOutVal)
 	return nil
@@ -66,14 +80,39 @@ func (cli *anyClient) listToList(in interface{}, outVal reflect.Value) error {
 		basicOutVal = reflect.New(arrType).Elem()
 	} else {
 		// If the output is a slice, create a new slice of the appropriate type and size.
+		if err := cli.checkLength(inVal.Len()); err != nil {
+			return err
+		}
 		basicOutVal = reflect.MakeSlice(outVal.Type(), inVal.Len(), inVal.Len())
 	}
 	for i := 0; i < inVal.Len(); i++ {
 		v := inVal.Index(i).Interface()
-		if err := cli.decodeAny(v, basicOutVal.Index(i)); err != nil {
+		if err := cli.decodeAt(indexSegment(i), v, basicOutVal.Index(i)); err != nil {
 			return err
 		}
 	}
 	outVal.Set(basicOutVal)
 	return nil
 }
+
+// stringToChars converts a string into a slice of runes (int32 elements) or bytes (uint8 elements).
+func (cli *anyClient) stringToChars(in string, outVal reflect.Value) error {
+	if err := cli.checkLength(len(in)); err != nil {
+		return err
+	}
+	if outVal.Type().Elem().Kind() == reflect.Uint8 {
+		basicOutVal := reflect.MakeSlice(outVal.Type(), len(in), len(in))
+		for i := 0; i < len(in); i++ {
+			basicOutVal.Index(i).SetUint(uint64(in[i]))
+		}
+		outVal.Set(basicOutVal)
+		return nil
+	}
+	runes := []rune(in)
+	basicOutVal := reflect.MakeSlice(outVal.Type(), len(runes), len(runes))
+	for i, r := range runes {
+		basicOutVal.Index(i).SetInt(int64(r))
+	}
+	outVal.Set(basicOutVal)
+	return nil
+}
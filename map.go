@@ -3,6 +3,7 @@ package goany
 import (
 	"github.com/pkg/errors"
 	"reflect"
+	"strings"
 )
 
 // decodeMap decodes an input value into a map output value. The input can be a map, struct, list (array or slice),
@@ -14,6 +15,9 @@ func (cli *anyClient) decodeMap(in interface{}, outVal reflect.Value) error {
 	case reflect.Map:
 		return cli.mapToMap(in, outVal)
 	case reflect.Struct:
+		if cli.options.flatKeys {
+			return cli.structToFlatMap(inVal, outVal)
+		}
 		return cli.structToMap(in, outVal)
 	case reflect.Array, reflect.Slice:
 		return cli.listToMap(in, outVal)
@@ -28,15 +32,19 @@ func (cli *anyClient) decodeMap(in interface{}, outVal reflect.Value) error {
 
 // mapToMap converts a map input into a map output value. It decodes each key-value pair from the input map
 // and sets the corresponding key-value pair in the output map. The output map's key and value types are determined
-// dynamically at runtime.
+// dynamically at runtime. The entries are visited in the map key order of the options.
 func (cli *anyClient) mapToMap(in interface{}, outVal reflect.Value) error {
 	basicOutVal := reflect.MakeMap(outVal.Type())
 	basicOutKey := basicOutVal.Type().Key()
 	basicOutElem := basicOutVal.Type().Elem()
 
-	inVal := reflect.ValueOf(in)
+	inVal := reflect.Indirect(reflect.ValueOf(in))
+	if err := cli.checkLength(inVal.Len()); err != nil {
+		return err
+	}
+	cli.keepOutput(in, basicOutVal)
 
-	for _, k := range inVal.MapKeys() {
+	for _, k := range cli.options.mapKeys(inVal) {
 		currentKey := reflect.Indirect(reflect.New(basicOutKey))
 		if err := cli.decodeAny(k.Interface(), currentKey); err != nil {
 			return err
@@ -44,7 +52,7 @@ func (cli *anyClient) mapToMap(in interface{}, outVal reflect.Value) error {
 		inFieldVal := inVal.MapIndex(k).Interface()
 		currentValue := reflect.Indirect(reflect.New(basicOutElem))
 
-		if err := cli.decodeAny(inFieldVal, currentValue); err != nil {
+		if err := cli.decodeAt(keySegment(k), inFieldVal, currentValue); err != nil {
 			return err
 		}
 		basicOutVal.SetMapIndex(currentKey, currentValue)
@@ -87,7 +95,11 @@ func (cli *anyClient) structToMap(in interface{}, outVal reflect.Value) error {
 			inFieldVal = getUnexportedField(inFieldVal)
 		}
 
-		if err := cli.decodeAny(inFieldVal.Interface(), currentValue); err != nil {
+		fieldCli, err := cli.fieldClient(inField.fieldStruct)
+		if err != nil {
+			return err
+		}
+		if err := fieldCli.decodeAt(flatSegment{key: inField.fieldName}, inFieldVal.Interface(), currentValue); err != nil {
 			return err
 		}
 		basicOutVal.SetMapIndex(currentKey, currentValue)
@@ -96,37 +108,44 @@ func (cli *anyClient) structToMap(in interface{}, outVal reflect.Value) error {
 	return nil
 }
 
-// listToMap converts a list input (array or slice) into a map output value. If the `mapKeyField` option is set and the
-// elements are structs, the specified field within each struct is used as the map key. Otherwise, the list index is used
-// as the map key. Each element of the list is decoded and added to the map.
+// structToFlatMap converts a struct input into a map output value with flat keys, see Flatten.
+func (cli *anyClient) structToFlatMap(inVal reflect.Value, outVal reflect.Value) error {
+	flat := make(map[string]interface{})
+	if err := cli.flatten("", inVal, cli.options.flatSep, flat); err != nil {
+		return err
+	}
+	return cli.mapToMap(flat, outVal)
+}
+
+// listToMap converts a list input (array or slice) into a map output value. If the `mapKeyField` or `mapKeyFields`
+// options are set and the elements are structs or maps, the specified fields within each element are used as the
+// map key. Otherwise, the list index is used as the map key. Each element of the list is decoded and added to the map,
+// grouped into a slice with the `mapGroup` option, or following the `mapDuplicate` policy when keys repeat.
+// If the `mapEntries` option is set, the elements are entries holding the key and value, see entriesToMap.
 func (cli *anyClient) listToMap(in interface{}, outVal reflect.Value) error {
+	_, inValue := ReflectTypeValue(in)
+	if err := cli.checkLength(inValue.Len()); err != nil {
+		return err
+	}
+	if cli.options.mapEntries {
+		return cli.entriesToMap(inValue, outVal)
+	}
+
 	basicOutVal := reflect.MakeMap(outVal.Type())
 	basicOutKey := basicOutVal.Type().Key()
 	basicOutElem := basicOutVal.Type().Elem()
-
-	_, inValue := ReflectTypeValue(in)
+	if cli.options.mapGroup && basicOutElem.Kind() != reflect.Slice {
+		return errors.Errorf(ErrInToOut, in, outVal.Type().String())
+	}
+	keyFields := cli.options.keyFields()
 
 	for i := 0; i < inValue.Len(); i++ {
 		currentKey := reflect.Indirect(reflect.New(basicOutKey))
-		currentValue := reflect.Indirect(reflect.New(basicOutElem))
-
 		inFiledVal := inValue.Index(i)
-		inFiledValElem := reflect.Indirect(inFiledVal)
-
-		// if mapKeyField is set, use the specified field as the map key
-		if cli.options.mapKeyField != "" && inFiledValElem.Kind() == reflect.Struct {
-			var keyFieldVal reflect.Value
-			for j := 0; j < inFiledValElem.NumField(); j++ {
-				fileName := GetFieldNameByTag(inFiledValElem.Type().Field(j), cli.options.tagName)
-				if fileName == cli.options.mapKeyField {
-					keyFieldVal = inFiledValElem.Field(j)
-					break
-				}
-			}
-			if keyFieldVal.Kind() == reflect.Invalid {
-				return errors.Errorf(ErrFieldNoFound, cli.options.mapKeyField)
-			}
-			if err := cli.decodeAny(keyFieldVal.Interface(), currentKey); err != nil {
+
+		// if mapKeyField is set, use the specified fields as the map key
+		if len(keyFields) > 0 && isKeyedElement(inFiledVal) {
+			if err := cli.decodeElementKey(inFiledVal, keyFields, currentKey); err != nil {
 				return err
 			}
 		} else {
@@ -134,7 +153,30 @@ func (cli *anyClient) listToMap(in interface{}, outVal reflect.Value) error {
 				return err
 			}
 		}
-		if err := cli.decodeAny(inFiledVal.Interface(), currentValue); err != nil {
+
+		if cli.options.mapGroup {
+			group := basicOutVal.MapIndex(currentKey)
+			if !group.IsValid() {
+				group = reflect.MakeSlice(basicOutElem, 0, 1)
+			}
+			currentValue := reflect.Indirect(reflect.New(basicOutElem.Elem()))
+			if err := cli.decodeAt(indexSegment(i), inFiledVal.Interface(), currentValue); err != nil {
+				return err
+			}
+			basicOutVal.SetMapIndex(currentKey, reflect.Append(group, currentValue))
+			continue
+		}
+
+		if basicOutVal.MapIndex(currentKey).IsValid() {
+			switch cli.options.mapDuplicate {
+			case DuplicateFirst:
+				continue
+			case DuplicateError:
+				return errors.Errorf(ErrDuplicateKey, currentKey.Interface())
+			}
+		}
+		currentValue := reflect.Indirect(reflect.New(basicOutElem))
+		if err := cli.decodeAt(indexSegment(i), inFiledVal.Interface(), currentValue); err != nil {
 			return err
 		}
 		basicOutVal.SetMapIndex(currentKey, currentValue)
@@ -142,3 +184,60 @@ func (cli *anyClient) listToMap(in interface{}, outVal reflect.Value) error {
 	outVal.Set(basicOutVal)
 	return nil
 }
+
+// keyFields returns the fields list elements are keyed by, none if elements are keyed by index.
+func (op *Options) keyFields() []string {
+	if len(op.mapKeyFields) > 0 {
+		return op.mapKeyFields
+	}
+	if op.mapKeyField != "" {
+		return []string{op.mapKeyField}
+	}
+	return nil
+}
+
+// isKeyedElement reports whether a list element can be keyed by its fields, that is a struct or a map.
+func isKeyedElement(elemVal reflect.Value) bool {
+	for (elemVal.Kind() == reflect.Ptr || elemVal.Kind() == reflect.Interface) && !elemVal.IsNil() {
+		elemVal = elemVal.Elem()
+	}
+	return elemVal.Kind() == reflect.Struct || elemVal.Kind() == reflect.Map
+}
+
+// decodeElementKey decodes the map key of a list element from its key fields. A single field is decoded
+// as it is, several fields form a composite key: a struct key is filled by field name, an array key gets the
+// values in order, and other keys get the string forms of the values joined by the key separator.
+func (cli *anyClient) decodeElementKey(elemVal reflect.Value, keyFields []string, outKey reflect.Value) error {
+	values := make([]interface{}, len(keyFields))
+	for i, name := range keyFields {
+		fieldVal, ok := cli.elementField(elemVal, name)
+		if !ok {
+			return errors.Errorf(ErrFieldNoFound, name)
+		}
+		values[i] = fieldVal.Interface()
+	}
+	if len(values) == 1 {
+		return cli.decodeAny(values[0], outKey)
+	}
+
+	switch outKey.Kind() {
+	case reflect.Struct:
+		composite := make(map[string]interface{}, len(values))
+		for i, name := range keyFields {
+			composite[name] = values[i]
+		}
+		return cli.decodeAny(composite, outKey)
+	case reflect.Array:
+		return cli.decodeAny(values, outKey)
+	default:
+		parts := make([]string, len(values))
+		for i, v := range values {
+			s, err := toStringE(v, *cli.options)
+			if err != nil {
+				return err
+			}
+			parts[i] = s
+		}
+		return cli.decodeAny(strings.Join(parts, cli.options.mapKeySep), outKey)
+	}
+}
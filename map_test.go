@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -263,6 +264,23 @@ func TestDecodeMap_String(t *testing.T) {
 			input:    `[{"a": "1"}, {"b": "2", "c": {"d": "3"}}]`,
 			expected: map[string]interface{}{"0": map[string]interface{}{"a": "1"}, "1": map[string]interface{}{"b": "2", "c": map[string]interface{}{"d": "3"}}},
 		},
+		{
+			name:     "Test with string numbers, float64 in interfaces",
+			input:    `{"a": 1, "b": {"c": [2.5, 9007199254740993]}}`,
+			expected: map[string]interface{}{"a": 1.0, "b": map[string]interface{}{"c": []interface{}{2.5, 9007199254740993.0}}},
+		},
+		{
+			name:     "Test with string numbers, use number",
+			input:    `{"a": 1, "b": [9007199254740993]}`,
+			op:       NewOptions().SetUseNumber(true),
+			expected: map[string]interface{}{"a": json.Number("1"), "b": []interface{}{json.Number("9007199254740993")}},
+		},
+		{
+			name:     "Test with string numbers, to typed map",
+			input:    `{"a": 9007199254740993}`,
+			output:   make(map[string]int64),
+			expected: map[string]int64{"a": 9007199254740993},
+		},
 		{
 			name:     "Test with string slice strut, to slice map",
 			input:    `[{"a": "1"}, {"b": "2"}]`,
@@ -298,3 +316,192 @@ func TestDecodeMap_List2(t *testing.T) {
 	err := ToAny(ints, &m)
 	assert.Error(t, err)
 }
+
+func TestDecodeMap_TimeExport(t *testing.T) {
+	type event struct {
+		Name    string     `json:"name"`
+		At      time.Time  `json:"at"`
+		Day     time.Time  `json:"day" goany:",format=2006-01-02"`
+		Local   time.Time  `json:"local" goany:",tz=Asia/Shanghai,format=15:04"`
+		Deleted *time.Time `json:"deleted"`
+	}
+	at := time.Date(2024, 1, 2, 3, 4, 5, 6000000, time.UTC)
+	in := event{Name: "a", At: at, Day: at, Local: at, Deleted: &at}
+
+	tests := []struct {
+		name     string
+		op       *Options
+		expected map[string]interface{}
+	}{
+		{
+			name: "Test with keep time",
+			op:   NewOptions(),
+			expected: map[string]interface{}{"name": "a", "at": at, "day": "2024-01-02", "local": "11:04",
+				"deleted": &at},
+		},
+		{
+			name: "Test with string",
+			op:   NewOptions().SetTimeExport(TimeExportString).SetTimeFormat(time.RFC3339),
+			expected: map[string]interface{}{"name": "a", "at": "2024-01-02T03:04:05Z", "day": "2024-01-02",
+				"local": "11:04", "deleted": "2024-01-02T03:04:05Z"},
+		},
+		{
+			name: "Test with unix",
+			op:   NewOptions().SetTimeExport(TimeExportUnix),
+			expected: map[string]interface{}{"name": "a", "at": at.Unix(), "day": at.Unix(), "local": at.Unix(),
+				"deleted": at.Unix()},
+		},
+		{
+			name: "Test with unix milli",
+			op:   NewOptions().SetTimeExport(TimeExportUnixMilli),
+			expected: map[string]interface{}{"name": "a", "at": at.UnixMilli(), "day": at.UnixMilli(),
+				"local": at.UnixMilli(), "deleted": at.UnixMilli()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out map[string]interface{}
+			err := ToAny(in, &out, *tt.op)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, out)
+		})
+	}
+
+	t.Run("Test with nest detail", func(t *testing.T) {
+		type wrapper struct {
+			Event event `json:"event"`
+		}
+		var out interface{}
+		op := NewOptions().SetStructToMapDetail(true).SetTimeExport(TimeExportString)
+		err := ToAny(wrapper{Event: in}, &out, *op)
+		assert.NoError(t, err)
+		nested := out.(map[string]interface{})["event"].(map[string]interface{})
+		assert.Equal(t, "2024-01-02 03:04:05", nested["at"])
+		assert.Equal(t, "2024-01-02", nested["day"])
+	})
+
+	t.Run("Test with format tag parse", func(t *testing.T) {
+		var out event
+		err := ToAny(map[string]interface{}{"day": "2024-01-02", "local": "11:04"}, &out)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), out.Day)
+		sh, _ := time.LoadLocation("Asia/Shanghai")
+		assert.Equal(t, time.Date(0, 1, 1, 11, 4, 0, 0, sh), out.Local)
+	})
+
+	t.Run("Test with map to map", func(t *testing.T) {
+		var out map[string]interface{}
+		err := ToAny(map[string]time.Time{"at": at}, &out, *NewOptions().SetTimeExport(TimeExportUnix))
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"at": at.Unix()}, out)
+	})
+}
+
+func TestDecodeMap_ListKeyed(t *testing.T) {
+	type user struct {
+		Id     int    `json:"id"`
+		Tenant string `json:"tenant"`
+		Name   string `json:"name"`
+	}
+	users := []user{
+		{Id: 1, Tenant: "a", Name: "ann"},
+		{Id: 2, Tenant: "b", Name: "bob"},
+		{Id: 1, Tenant: "b", Name: "ben"},
+	}
+
+	t.Run("Test with duplicate policy", func(t *testing.T) {
+		op := NewOptions().SetMapKeyField("id")
+		var last map[int]user
+		err := ToAny(users, &last, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, "ben", last[1].Name)
+
+		var first map[int]user
+		err = ToAny(users, &first, *op.SetMapDuplicate(DuplicateFirst))
+		assert.NoError(t, err)
+		assert.Equal(t, "ann", first[1].Name)
+		assert.Equal(t, "bob", first[2].Name)
+
+		var strict map[int]user
+		err = ToAny(users, &strict, *op.SetMapDuplicate(DuplicateError))
+		assert.Equal(t, errors.Errorf(ErrDuplicateKey, 1).Error(), err.Error())
+	})
+
+	t.Run("Test with group", func(t *testing.T) {
+		var out map[string][]user
+		err := ToAny(users, &out, *NewOptions().SetMapKeyField("tenant").SetMapGroup(true))
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]user{"a": {users[0]}, "b": {users[1], users[2]}}, out)
+
+		var names map[int][]string
+		err = ToAny([]map[string]interface{}{{"id": 1, "name": "x"}, {"id": 1, "name": "y"}}, &names,
+			*NewOptions().SetMapKeyField("id").SetMapGroup(true).AddHook(func(in interface{}, out reflect.Value) (int, error) {
+				if m, ok := in.(map[string]interface{}); ok && out.Kind() == reflect.String {
+					out.SetString(m["name"].(string))
+					return DecodeSkip, nil
+				}
+				return DecodeContinue, nil
+			}))
+		assert.NoError(t, err)
+		assert.Equal(t, map[int][]string{1: {"x", "y"}}, names)
+
+		var wrong map[string]user
+		err = ToAny(users, &wrong, *NewOptions().SetMapKeyField("tenant").SetMapGroup(true))
+		assert.Error(t, err)
+	})
+
+	t.Run("Test with composite key", func(t *testing.T) {
+		op := NewOptions().SetMapKeyFields("tenant", "id").SetMapDuplicate(DuplicateError)
+		var joined map[string]string
+		err := ToAny(users, &joined, *NewOptions().SetMapKeyFields("tenant", "id").AddHook(nameHook))
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"a:1": "ann", "b:2": "bob", "b:1": "ben"}, joined)
+
+		err = ToAny(users, &joined, *NewOptions().SetMapKeyFields("tenant", "id").SetMapKeySeparator("/").AddHook(nameHook))
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"a/1": "ann", "b/2": "bob", "b/1": "ben"}, joined)
+
+		type key struct {
+			Tenant string `json:"tenant"`
+			Id     int    `json:"id"`
+		}
+		var byStruct map[key]user
+		err = ToAny(users, &byStruct, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, "ben", byStruct[key{Tenant: "b", Id: 1}].Name)
+
+		var byArray map[[2]string]user
+		err = ToAny(users, &byArray, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, "bob", byArray[[2]string{"b", "2"}].Name)
+	})
+
+	t.Run("Test with map elements", func(t *testing.T) {
+		in := []interface{}{
+			map[string]interface{}{"id": 7, "name": "x"},
+			&map[string]interface{}{"id": 8, "name": "y"},
+		}
+		var out map[int]map[string]interface{}
+		err := ToAny(in, &out, *NewOptions().SetMapKeyField("id"))
+		assert.NoError(t, err)
+		assert.Equal(t, "x", out[7]["name"])
+		assert.Equal(t, "y", out[8]["name"])
+
+		err = ToAny(in, &out, *NewOptions().SetMapKeyField("missing"))
+		assert.Equal(t, errors.Errorf(ErrFieldNoFound, "missing").Error(), err.Error())
+	})
+}
+
+// nameHook decodes a user like element into a string output as its name.
+func nameHook(in interface{}, out reflect.Value) (int, error) {
+	if out.Kind() != reflect.String {
+		return DecodeContinue, nil
+	}
+	if v := reflect.Indirect(reflect.ValueOf(in)); v.Kind() == reflect.Struct {
+		if name := v.FieldByName("Name"); name.IsValid() {
+			out.SetString(name.String())
+			return DecodeSkip, nil
+		}
+	}
+	return DecodeContinue, nil
+}
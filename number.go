@@ -0,0 +1,469 @@
+package goany
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// toIntKindE converts an interface to a signed integer that fits in the given kind.
+// The value is converted by toInt64E first and then checked against the range of the kind,
+// out of range values return an error, or are clamped to the range if the policy is LossySaturate.
+func toIntKindE(v interface{}, kind reflect.Kind, op Options) (int64, error) {
+	i, err := toInt64E(v, op)
+	if err != nil {
+		return 0, err
+	}
+	bits := kindBits(kind)
+	if bits == 64 {
+		return i, nil
+	}
+	minVal, maxVal := int64(-1)<<(bits-1), int64(1)<<(bits-1)-1
+	switch {
+	case i < minVal:
+		if op.lossyPolicy == LossySaturate {
+			return minVal, nil
+		}
+		return 0, errors.Errorf(ErrValueOverflow, v, kind.String())
+	case i > maxVal:
+		if op.lossyPolicy == LossySaturate {
+			return maxVal, nil
+		}
+		return 0, errors.Errorf(ErrValueOverflow, v, kind.String())
+	}
+	return i, nil
+}
+
+// toUintKindE converts an interface to an unsigned integer that fits in the given kind,
+// see toIntKindE.
+func toUintKindE(v interface{}, kind reflect.Kind, op Options) (uint64, error) {
+	u, err := toUint64E(v, op)
+	if err != nil {
+		return 0, err
+	}
+	bits := kindBits(kind)
+	if bits == 64 {
+		return u, nil
+	}
+	if maxVal := uint64(1)<<bits - 1; u > maxVal {
+		if op.lossyPolicy == LossySaturate {
+			return maxVal, nil
+		}
+		return 0, errors.Errorf(ErrValueOverflow, v, kind.String())
+	}
+	return u, nil
+}
+
+// toFloatKindE converts an interface to a float that fits in the given kind.
+// For float32, finite values beyond math.MaxFloat32 return an error or are clamped with LossySaturate,
+// and with LossyError a value that changes when rounded to float32 returns an error.
+func toFloatKindE(v interface{}, kind reflect.Kind, op Options) (float64, error) {
+	f, err := toFloat64E(v, op)
+	if err != nil {
+		return 0, err
+	}
+	if kind != reflect.Float32 {
+		return f, nil
+	}
+	return narrowFloat32(f, v, kind, op)
+}
+
+// toComplexKindE converts an interface to a complex that fits in the given kind,
+// for complex64 both parts are checked like a float32.
+func toComplexKindE(v interface{}, kind reflect.Kind, op Options) (complex128, error) {
+	c, err := toComplex128E(v, op)
+	if err != nil || kind != reflect.Complex64 {
+		return c, err
+	}
+	re, err := narrowFloat32(real(c), v, kind, op)
+	if err != nil {
+		return 0, err
+	}
+	im, err := narrowFloat32(imag(c), v, kind, op)
+	if err != nil {
+		return 0, err
+	}
+	return complex(re, im), nil
+}
+
+// narrowFloat32 checks that f fits in a float32, see toFloatKindE.
+func narrowFloat32(f float64, in interface{}, kind reflect.Kind, op Options) (float64, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return f, nil
+	}
+	if math.Abs(f) > math.MaxFloat32 {
+		if op.lossyPolicy == LossySaturate {
+			return math.Copysign(math.MaxFloat32, f), nil
+		}
+		return 0, errors.Errorf(ErrValueOverflow, in, kind.String())
+	}
+	if op.lossyPolicy == LossyError && float64(float32(f)) != f {
+		return 0, errors.Errorf(ErrPrecisionLoss, in, kind.String())
+	}
+	return f, nil
+}
+
+// floatToInt64 converts a float to an int64, the fraction is handled by the lossy policy.
+func floatToInt64(f float64, in interface{}, op Options) (int64, error) {
+	f, err := dropFraction(f, in, "int64", op)
+	if err != nil {
+		return 0, err
+	}
+	// float64(math.MaxInt64) rounds up to 2^63, so the upper bound must be exclusive.
+	switch {
+	case f >= math.MaxInt64:
+		if op.lossyPolicy == LossySaturate {
+			return math.MaxInt64, nil
+		}
+		return 0, errors.Errorf(ErrValueOverflow, in, "int64")
+	case f < math.MinInt64:
+		if op.lossyPolicy == LossySaturate {
+			return math.MinInt64, nil
+		}
+		return 0, errors.Errorf(ErrValueOverflow, in, "int64")
+	}
+	return int64(f), nil
+}
+
+// floatToUint64 converts a float to an uint64, the fraction is handled by the lossy policy.
+func floatToUint64(f float64, in interface{}, op Options) (uint64, error) {
+	if f < 0 {
+		if op.lossyPolicy == LossySaturate {
+			return 0, nil
+		}
+		return 0, errors.Errorf(ErrValueOverflow, in, "uint64")
+	}
+	f, err := dropFraction(f, in, "uint64", op)
+	if err != nil {
+		return 0, err
+	}
+	if f >= math.MaxUint64 {
+		if op.lossyPolicy == LossySaturate {
+			return math.MaxUint64, nil
+		}
+		return 0, errors.Errorf(ErrValueOverflow, in, "uint64")
+	}
+	return uint64(f), nil
+}
+
+// dropFraction removes the fraction of f according to the lossy policy.
+// LossyError rejects any fraction, LossyRound rounds half away from zero,
+// LossyTruncate and LossySaturate truncate toward zero.
+func dropFraction(f float64, in interface{}, target string, op Options) (float64, error) {
+	if math.IsNaN(f) {
+		return 0, errors.Errorf(ErrInToOut, in, target)
+	}
+	if f == math.Trunc(f) {
+		return f, nil
+	}
+	switch op.lossyPolicy {
+	case LossyError:
+		return 0, errors.Errorf(ErrPrecisionLoss, in, target)
+	case LossyRound:
+		return math.Round(f), nil
+	default:
+		return math.Trunc(f), nil
+	}
+}
+
+// kindBits returns the size in bits of a numeric kind.
+func kindBits(kind reflect.Kind) int {
+	switch kind {
+	case reflect.Int8, reflect.Uint8:
+		return 8
+	case reflect.Int16, reflect.Uint16:
+		return 16
+	case reflect.Int32, reflect.Uint32, reflect.Float32:
+		return 32
+	case reflect.Int, reflect.Uint, reflect.Uintptr:
+		return strconv.IntSize
+	default:
+		return 64
+	}
+}
+
+// numberUnits maps SI and IEC suffixes to their multiplier, a trailing "B" for bytes is optional.
+var numberUnits = map[string]uint64{
+	"k": 1e3, "K": 1e3, "M": 1e6, "G": 1e9, "T": 1e12, "P": 1e15, "E": 1e18,
+	"Ki": 1 << 10, "Mi": 1 << 20, "Gi": 1 << 30, "Ti": 1 << 40, "Pi": 1 << 50, "Ei": 1 << 60,
+}
+
+// stringToInt64 parses a string to an int64. Without a number format it behaves like
+// strconv.ParseInt in base 10, otherwise the string is parsed by parseNumberString.
+func stringToInt64(s string, op Options) (int64, error) {
+	if op.numberFormat == 0 {
+		i, err := strconv.ParseInt(s, 10, 64)
+		if errors.Is(err, strconv.ErrRange) {
+			// strconv returns the nearest bound on a range error.
+			if op.lossyPolicy == LossySaturate {
+				return i, nil
+			}
+			return 0, errors.Errorf(ErrValueOverflow, s, "int64")
+		}
+		return i, err
+	}
+	u, neg, err := parseNumberString(s, op)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case neg && u > 1<<63:
+		if op.lossyPolicy == LossySaturate {
+			return math.MinInt64, nil
+		}
+		return 0, errors.Errorf(ErrValueOverflow, s, "int64")
+	case neg:
+		return -int64(u-1) - 1, nil
+	case u > math.MaxInt64:
+		if op.lossyPolicy == LossySaturate {
+			return math.MaxInt64, nil
+		}
+		return 0, errors.Errorf(ErrValueOverflow, s, "int64")
+	}
+	return int64(u), nil
+}
+
+// stringToUint64 parses a string to an uint64, see stringToInt64.
+func stringToUint64(s string, op Options) (uint64, error) {
+	if op.numberFormat == 0 {
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err == nil && i >= 0 {
+			return uint64(i), nil
+		}
+		if i < 0 {
+			if op.lossyPolicy == LossySaturate {
+				return 0, nil
+			}
+			return 0, errors.Errorf(ErrValueOverflow, s, "uint64")
+		}
+		u, err := strconv.ParseUint(s, 10, 64)
+		if errors.Is(err, strconv.ErrRange) {
+			if op.lossyPolicy == LossySaturate {
+				return u, nil
+			}
+			return 0, errors.Errorf(ErrValueOverflow, s, "uint64")
+		}
+		return u, err
+	}
+	u, neg, err := parseNumberString(s, op)
+	if err != nil {
+		return 0, err
+	}
+	if neg && u != 0 {
+		if op.lossyPolicy == LossySaturate {
+			return 0, nil
+		}
+		return 0, errors.Errorf(ErrValueOverflow, s, "uint64")
+	}
+	return u, nil
+}
+
+// stringToFloat64 parses a string to a float64. Without a number format it behaves like
+// strconv.ParseFloat, otherwise separators, units and radix prefixes are handled first.
+func stringToFloat64(s string, op Options) (float64, error) {
+	if op.numberFormat == 0 {
+		return strconv.ParseFloat(s, 64)
+	}
+	num, mult, err := normalizeNumber(s, op)
+	if err != nil {
+		return 0, err
+	}
+	if neg, base, digits := splitRadix(num, op); base != 10 {
+		u, err := strconv.ParseUint(digits, base, 64)
+		if err != nil {
+			return 0, errors.Errorf(ErrUnableConvertFloat64, s)
+		}
+		if neg {
+			return -float64(u), nil
+		}
+		return float64(u), nil
+	}
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, errors.Errorf(ErrUnableConvertFloat64, s)
+	}
+	return f * float64(mult), nil
+}
+
+// parseNumberString parses a string holding an integer according to the number format of op,
+// and returns its magnitude and sign. Integral float notation such as "1e3" or "12.0" and
+// fractional values with a unit such as "1.5k" are accepted, any remaining fraction is an error.
+func parseNumberString(s string, op Options) (uint64, bool, error) {
+	num, mult, err := normalizeNumber(s, op)
+	if err != nil {
+		return 0, false, err
+	}
+	neg, base, digits := splitRadix(num, op)
+	u, err := strconv.ParseUint(digits, base, 64)
+	switch {
+	case err == nil && u <= math.MaxUint64/mult:
+		return u * mult, neg, nil
+	case err == nil || errors.Is(err, strconv.ErrRange):
+		return numberOverflow(s, neg, op)
+	}
+	if base != 10 || (op.numberFormat&NumberExponent == 0 && mult == 1) {
+		return 0, false, errors.Errorf(ErrUnableConvertInt64, s)
+	}
+	f, err := strconv.ParseFloat(digits, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false, errors.Errorf(ErrUnableConvertInt64, s)
+	}
+	if f*float64(mult) >= math.MaxUint64 {
+		return numberOverflow(s, neg, op)
+	}
+	u, ok := scaleDecimal(digits, f*float64(mult), mult)
+	if !ok {
+		return 0, false, errors.Errorf(ErrPrecisionLoss, s, "integer")
+	}
+	return u, neg, nil
+}
+
+// scaleDecimal multiplies the decimal digits by mult exactly, so "1.001" with the unit k is
+// 1001 and not the float 1000.9999999999999. f is the float estimate of the product and
+// must be below 2^64, it keeps the exponent bounded before the exact computation.
+func scaleDecimal(digits string, f float64, mult uint64) (uint64, bool) {
+	if f < 1 {
+		// Only an exact zero is an integer below one.
+		mantissa, _, _ := strings.Cut(strings.ToLower(digits), "e")
+		return 0, strings.IndexAny(mantissa, "123456789") < 0
+	}
+	r, ok := new(big.Rat).SetString(digits)
+	if !ok {
+		return 0, false
+	}
+	r.Mul(r, new(big.Rat).SetUint64(mult))
+	if !r.IsInt() || !r.Num().IsUint64() {
+		return 0, false
+	}
+	return r.Num().Uint64(), true
+}
+
+// numberOverflow handles a numeric string whose magnitude does not fit in an uint64.
+// With LossySaturate the largest magnitude is returned and the callers clamp it to their kind,
+// otherwise an overflow error is returned.
+func numberOverflow(s string, neg bool, op Options) (uint64, bool, error) {
+	if op.lossyPolicy == LossySaturate {
+		return math.MaxUint64, neg, nil
+	}
+	return 0, false, errors.Errorf(ErrValueOverflow, s, "uint64")
+}
+
+// normalizeNumber prepares a numeric string for strconv according to the number format of op.
+// It trims spaces, removes the unit suffix, underscores and thousands separators, replaces the
+// decimal separator with ".", and returns the cleaned string with the unit multiplier.
+func normalizeNumber(s string, op Options) (string, uint64, error) {
+	format := op.numberFormat
+	if format&NumberTrimSpace != 0 {
+		s = strings.TrimSpace(s)
+	}
+
+	mult := uint64(1)
+	if format&NumberUnits != 0 && !hasRadixPrefix(s) {
+		s, mult = cutNumberUnit(s)
+	}
+
+	if format&NumberUnderscore != 0 && strings.Contains(s, "_") {
+		if strings.HasPrefix(s, "_") || strings.HasSuffix(s, "_") || strings.Contains(s, "__") {
+			return "", 0, errors.Errorf(ErrUnableConvertInt64, s)
+		}
+		s = strings.ReplaceAll(s, "_", "")
+	}
+
+	if format&NumberThousands != 0 {
+		var ok bool
+		if s, ok = removeThousands(s, op.thousandsSep, op.decimalSep); !ok {
+			return "", 0, errors.Errorf(ErrUnableConvertInt64, s)
+		}
+	}
+	return s, mult, nil
+}
+
+// cutNumberUnit removes a known unit suffix from s and returns its multiplier.
+// Unknown suffixes are left in place, so that parsing fails later.
+func cutNumberUnit(s string) (string, uint64) {
+	end := len(s)
+	for end > 0 && unicode.IsLetter(rune(s[end-1])) {
+		end--
+	}
+	suffix := s[end:]
+	if suffix == "" {
+		return s, 1
+	}
+	num := strings.TrimRightFunc(s[:end], unicode.IsSpace)
+	if suffix == "B" {
+		return num, 1
+	}
+	if mult, ok := numberUnits[strings.TrimSuffix(suffix, "B")]; ok {
+		return num, mult
+	}
+	return s, 1
+}
+
+// removeThousands removes the thousands separator from s, checking that every group after
+// the first has exactly three digits, and replaces the decimal separator with ".".
+func removeThousands(s, thousandsSep, decimalSep string) (string, bool) {
+	if thousandsSep == "" {
+		thousandsSep = ","
+	}
+	if decimalSep == "" {
+		decimalSep = "."
+	}
+	intPart, fracPart, hasFrac := strings.Cut(s, decimalSep)
+	if strings.Contains(intPart, thousandsSep) {
+		groups := strings.Split(intPart, thousandsSep)
+		first := strings.TrimLeft(groups[0], "+-")
+		if len(first) == 0 || len(first) > 3 {
+			return s, false
+		}
+		for _, g := range groups[1:] {
+			if len(g) != 3 {
+				return s, false
+			}
+		}
+		intPart = strings.Join(groups, "")
+	}
+	if hasFrac {
+		return intPart + "." + fracPart, true
+	}
+	return intPart, true
+}
+
+// splitRadix splits a sign and a "0x", "0o" or "0b" prefix off num if NumberRadix is set.
+func splitRadix(num string, op Options) (neg bool, base int, digits string) {
+	digits = num
+	if strings.HasPrefix(digits, "-") {
+		neg, digits = true, digits[1:]
+	} else if strings.HasPrefix(digits, "+") {
+		digits = digits[1:]
+	}
+	if op.numberFormat&NumberRadix == 0 || !hasRadixPrefix(digits) {
+		return neg, 10, digits
+	}
+	switch digits[1] {
+	case 'x', 'X':
+		base = 16
+	case 'o', 'O':
+		base = 8
+	default:
+		base = 2
+	}
+	return neg, base, digits[2:]
+}
+
+// hasRadixPrefix reports whether s, without its sign, starts with "0x", "0o" or "0b".
+func hasRadixPrefix(s string) bool {
+	s = strings.TrimLeft(s, "+-")
+	if len(s) < 3 || s[0] != '0' {
+		return false
+	}
+	switch s[1] {
+	case 'x', 'X', 'o', 'O', 'b', 'B':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,302 @@
+package goany
+
+import (
+	"math"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNarrowIntE(t *testing.T) {
+	t.Run("Test with int8 overflow", func(t *testing.T) {
+		_, err := ToInt8E(300)
+		assert.Error(t, err)
+		assert.Equal(t, int8(0), ToInt8(300))
+
+		v, err := ToInt8E(-128)
+		assert.NoError(t, err)
+		assert.Equal(t, int8(-128), v)
+	})
+
+	t.Run("Test with int16 and int32 overflow", func(t *testing.T) {
+		_, err := ToInt16E("40000")
+		assert.Error(t, err)
+
+		_, err = ToInt32E(int64(math.MaxInt32) + 1)
+		assert.Error(t, err)
+
+		v, err := ToInt32E(uint32(math.MaxInt32))
+		assert.NoError(t, err)
+		assert.Equal(t, int32(math.MaxInt32), v)
+	})
+
+	t.Run("Test with uint overflow", func(t *testing.T) {
+		_, err := ToUint8E(256)
+		assert.Error(t, err)
+
+		_, err = ToUint16E(-1)
+		assert.Error(t, err)
+
+		v, err := ToUint32E("4294967295")
+		assert.NoError(t, err)
+		assert.Equal(t, uint32(math.MaxUint32), v)
+
+		vu, err := ToUintE(12)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(12), vu)
+	})
+
+	t.Run("Test with saturate", func(t *testing.T) {
+		op := NewOptions().SetLossyPolicy(LossySaturate)
+		v, err := ToInt8E(300, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, int8(127), v)
+
+		v, err = ToInt8E(-1e30, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, int8(-128), v)
+
+		vu, err := ToUint8E(-5, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, uint8(0), vu)
+
+		v64, err := ToInt64E(uint64(math.MaxUint64), *op)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(math.MaxInt64), v64)
+	})
+
+	t.Run("Test with string overflow", func(t *testing.T) {
+		_, err := ToUint64E(-1)
+		assert.EqualError(t, err, errors.Errorf(ErrValueOverflow, -1, "uint64").Error())
+
+		_, err = ToUintE("-1")
+		assert.EqualError(t, err, errors.Errorf(ErrValueOverflow, "-1", "uint64").Error())
+
+		_, err = ToInt64E("9223372036854775808")
+		assert.EqualError(t, err, errors.Errorf(ErrValueOverflow, "9223372036854775808", "int64").Error())
+
+		_, err = ToUint64E("18446744073709551616")
+		assert.EqualError(t, err, errors.Errorf(ErrValueOverflow, "18446744073709551616", "uint64").Error())
+	})
+
+	t.Run("Test with string saturate", func(t *testing.T) {
+		op := NewOptions().SetLossyPolicy(LossySaturate)
+		tests := []struct {
+			input    string
+			format   int
+			expected int64
+		}{
+			{input: "9223372036854775808", expected: math.MaxInt64},
+			{input: "-9223372036854775809", expected: math.MinInt64},
+			{input: "1e30", format: NumberExponent, expected: math.MaxInt64},
+			{input: "-99999999999999999999k", format: NumberUnits, expected: math.MinInt64},
+		}
+		for _, tt := range tests {
+			v, err := ToInt64E(tt.input, *NewOptions().SetLossyPolicy(LossySaturate).SetNumberFormat(tt.format))
+			assert.NoError(t, err, tt.input)
+			assert.Equal(t, tt.expected, v, tt.input)
+		}
+
+		vu, err := ToUint64E("18446744073709551616", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(math.MaxUint64), vu)
+
+		vu, err = ToUint64E("-18446744073709551616", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(0), vu)
+
+		v8, err := ToUint8E("100000000000000000000", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, uint8(math.MaxUint8), v8)
+
+		vi, err := ToInt16E("-100000000000000000000", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, int16(math.MinInt16), vi)
+	})
+}
+
+func TestLossyPolicy(t *testing.T) {
+	t.Run("Test with truncate", func(t *testing.T) {
+		v, err := ToIntE(-2.7)
+		assert.NoError(t, err)
+		assert.Equal(t, -2, v)
+	})
+
+	t.Run("Test with round", func(t *testing.T) {
+		op := NewOptions().SetLossyPolicy(LossyRound)
+		v, err := ToIntE(2.5, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, v)
+
+		vu, err := ToUint64E(1.4, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), vu)
+	})
+
+	t.Run("Test with error", func(t *testing.T) {
+		op := NewOptions().SetLossyPolicy(LossyError)
+		_, err := ToInt64E(2.5, *op)
+		assert.Error(t, err)
+
+		v, err := ToInt64E(2.0, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), v)
+
+		_, err = ToFloat32E(0.1, *op)
+		assert.Error(t, err)
+
+		vf, err := ToFloat32E(0.5, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, float32(0.5), vf)
+	})
+
+	t.Run("Test with float32 overflow", func(t *testing.T) {
+		_, err := ToFloat32E(1e300)
+		assert.Error(t, err)
+
+		vf, err := ToFloat32E(-1e300, *NewOptions().SetLossyPolicy(LossySaturate))
+		assert.NoError(t, err)
+		assert.Equal(t, float32(-math.MaxFloat32), vf)
+	})
+
+	t.Run("Test with NaN", func(t *testing.T) {
+		_, err := ToInt64E(math.NaN())
+		assert.Error(t, err)
+	})
+}
+
+func TestDecodeBasic_Range(t *testing.T) {
+	type small struct {
+		I8  int8    `json:"i8"`
+		U16 uint16  `json:"u16"`
+		F32 float32 `json:"f32"`
+	}
+
+	t.Run("Test with overflow field", func(t *testing.T) {
+		var out small
+		err := ToAny(map[string]interface{}{"i8": 300}, &out)
+		assert.Error(t, err)
+
+		err = ToAny(map[string]interface{}{"u16": 70000}, &out)
+		assert.Error(t, err)
+	})
+
+	t.Run("Test with saturate field", func(t *testing.T) {
+		var out small
+		op := NewOptions().SetLossyPolicy(LossySaturate)
+		err := ToAny(map[string]interface{}{"i8": 300, "u16": -1, "f32": 1e300}, &out, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, small{I8: 127, U16: 0, F32: math.MaxFloat32}, out)
+	})
+
+	t.Run("Test with round field", func(t *testing.T) {
+		var out small
+		op := NewOptions().SetLossyPolicy(LossyRound)
+		err := ToAny(`{"i8": 1.6, "u16": 2.4, "f32": 0.5}`, &out, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, small{I8: 2, U16: 2, F32: 0.5}, out)
+	})
+}
+
+func TestNumberFormat(t *testing.T) {
+	all := NumberRadix | NumberUnderscore | NumberThousands | NumberTrimSpace | NumberExponent | NumberUnits
+	op := NewOptions().SetNumberFormat(all)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected int64
+	}{
+		{name: "hex", input: "0x1F", expected: 31},
+		{name: "negative hex", input: "-0x10", expected: -16},
+		{name: "binary", input: "0b101", expected: 5},
+		{name: "octal", input: "0o17", expected: 15},
+		{name: "leading zero is decimal", input: "017", expected: 17},
+		{name: "underscore", input: "1_000_000", expected: 1000000},
+		{name: "thousands", input: "1,234", expected: 1234},
+		{name: "space", input: " 42 ", expected: 42},
+		{name: "exponent", input: "1e3", expected: 1000},
+		{name: "integral float", input: "12.0", expected: 12},
+		{name: "si unit", input: "1.5k", expected: 1500},
+		{name: "exact si unit", input: "1.001k", expected: 1001},
+		{name: "exact si units", input: "1.005M", expected: 1005000},
+		{name: "exact exponent", input: "1.003e3", expected: 1003},
+		{name: "fraction iec unit", input: "0.5KiB", expected: 512},
+		{name: "zero fraction", input: "0.0e5", expected: 0},
+		{name: "iec unit", input: "10MiB", expected: 10 << 20},
+		{name: "unit with space", input: "2 GB", expected: 2e9},
+		{name: "bytes", input: "512B", expected: 512},
+		{name: "min int64", input: "-9223372036854775808", expected: math.MinInt64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := ToInt64E(tt.input, *op)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+
+	t.Run("Test with invalid", func(t *testing.T) {
+		for _, in := range []string{"12.5", "1,23", "_1", "1__0", "0x1G", "10XB", "1e400", "1.0001k", "1e-400"} {
+			_, err := ToInt64E(in, *op)
+			assert.Error(t, err, in)
+		}
+		_, err := ToUint64E("-1k", *op)
+		assert.Error(t, err)
+		_, err = ToInt64E("16EiB", *op)
+		assert.Error(t, err)
+	})
+
+	t.Run("Test with default format", func(t *testing.T) {
+		for _, in := range []string{"0x1F", "1_000", "1,234", " 42 ", "1e3", "1k"} {
+			_, err := ToInt64E(in)
+			assert.Error(t, err, in)
+		}
+	})
+
+	t.Run("Test with flags are separate", func(t *testing.T) {
+		_, err := ToInt64E("0x1F", *NewOptions().SetNumberFormat(NumberUnderscore))
+		assert.Error(t, err)
+		_, err = ToInt64E("1_000", *NewOptions().SetNumberFormat(NumberRadix))
+		assert.Error(t, err)
+	})
+
+	t.Run("Test with locale separator", func(t *testing.T) {
+		op := NewOptions().SetNumberFormat(NumberThousands).SetThousandsSeparator(".").SetDecimalSeparator(",")
+		v, err := ToFloat64E("1.234,5", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, 1234.5, v)
+
+		vi, err := ToInt64E("1.234.567", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1234567), vi)
+	})
+
+	t.Run("Test with float and uint", func(t *testing.T) {
+		v, err := ToFloat64E("2.5k", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, 2500.0, v)
+
+		v, err = ToFloat64E("0xff", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, 255.0, v)
+
+		vu, err := ToUint64E([]byte("1KiB"), *op)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1024), vu)
+	})
+
+	t.Run("Test with struct field", func(t *testing.T) {
+		type config struct {
+			MaxSize int64 `json:"max_size"`
+			Mask    uint8 `json:"mask"`
+			Workers int   `json:"workers"`
+		}
+		var out config
+		in := map[string]interface{}{"max_size": "10MiB", "mask": "0xff", "workers": " 8 "}
+		err := ToAny(in, &out, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, config{MaxSize: 10 << 20, Mask: 255, Workers: 8}, out)
+	})
+}
@@ -3,28 +3,53 @@ package goany
 import (
 	"github.com/pkg/errors"
 	"reflect"
+	"strings"
 	"time"
 )
 
 var (
-	ErrDecodeStop           = errors.New("decode stop")
-	ErrBasic                = "unable to convert %#v(type %[1]T) to "
-	ErrInToOut              = "unable to convert %#v(type %[1]T) to %s"
-	ErrFieldNoFound         = "the specified field was not found key: %s"
-	ErrUnSupportType        = "unsupported out type %v"
-	ErrUnableConvertBasic   = ErrBasic + "basic type"
-	ErrUnableConvertInt64   = ErrBasic + "int64"
-	ErrUnableConvertUint64  = ErrBasic + "uint64"
-	ErrUnableConvertFloat64 = ErrBasic + "float64"
-	ErrUnableConvertString  = ErrBasic + "string"
-	ErrUnableConvertBool    = ErrBasic + "bool"
-	ErrUnableConvertTime    = ErrBasic + "time"
-	ErrNotJson              = "the input %#v(type %[1]T) is not json, or not map or slice"
-	ErrInNotPtr             = errors.New("if want to export a unexported field, the input must be of pointer type")
+	ErrDecodeStop            = errors.New("decode stop")
+	ErrBasic                 = "unable to convert %#v(type %[1]T) to "
+	ErrInToOut               = "unable to convert %#v(type %[1]T) to %s"
+	ErrFieldNoFound          = "the specified field was not found key: %s"
+	ErrUnSupportType         = "unsupported out type %v"
+	ErrUnableConvertBasic    = ErrBasic + "basic type"
+	ErrUnableConvertInt64    = ErrBasic + "int64"
+	ErrUnableConvertUint64   = ErrBasic + "uint64"
+	ErrUnableConvertFloat64  = ErrBasic + "float64"
+	ErrUnableConvertComplex  = ErrBasic + "complex128"
+	ErrUnableConvertString   = ErrBasic + "string"
+	ErrUnableConvertBool     = ErrBasic + "bool"
+	ErrUnableConvertTime     = ErrBasic + "time"
+	ErrUnableConvertDuration = ErrBasic + "duration"
+	ErrUnableParseTime       = ErrBasic + "time, tried layouts %q"
+	ErrValueOverflow         = "value %#v(type %[1]T) overflows %s"
+	ErrPrecisionLoss         = "value %#v(type %[1]T) loses precision when converted to %s"
+	ErrUnknownTimeZone       = "unknown time zone %q"
+	ErrDuplicateKey          = "duplicate map key %#v"
+	ErrFlatKeyConflict       = "flat key %q conflicts with another key"
+	ErrFlatIndex             = "flat key %q has list index %d beyond the number of keys"
+	ErrPathNotFound          = "path %q not found"
+	ErrPathNotSettable       = "path %q can not be set"
+	ErrCyclicValue           = "cyclic value of type %s"
+	ErrCyclicPath            = "cyclic value of type %s at %q"
+	ErrMaxDepth              = "max depth %d exceeded at %q"
+	ErrMaxInputSize          = "input of %d bytes exceeds the limit of %d bytes at %q"
+	ErrMaxLength             = "length %d exceeds the limit of %d at %q"
+	ErrMaxElements           = "more than %d elements at %q"
+	ErrNotPatch              = "the patch %#v(type %[1]T) is not a json patch or merge patch"
+	ErrPatchOp               = "unknown patch operation %q"
+	ErrPatchTest             = "patch test failed at %q"
+	ErrNotJson               = "the input %#v(type %[1]T) is not json, or not map or slice"
+	ErrInNotPtr              = errors.New("if want to export a unexported field, the input must be of pointer type")
 )
 
 const (
 	TagIgnore = "-"
+
+	TagOptions    = "goany"  // tag holding goany options of a field, such as `goany:",tz=America/New_York"`
+	TagTimeZone   = "tz"     // time zone option of a field, overrides the location of the options
+	TagTimeFormat = "format" // time format option of a field, used to parse and export its times
 )
 
 const (
@@ -33,6 +58,97 @@ const (
 	DecodeStop            // stop decoding
 )
 
+// The lossy policy decides what happens when a float is converted to an integer with a fraction,
+// when a value is out of the range of the target integer, or when a float64 does not fit in a float32.
+const (
+	LossyTruncate = iota // drop the fraction, reject out of range values, default
+	LossyError           // reject any conversion that loses data
+	LossySaturate        // drop the fraction, clamp out of range values to the nearest bound
+	LossyRound           // round the fraction half away from zero, reject out of range values
+)
+
+// Number formats accepted when parsing numeric strings, they can be combined with "|".
+// By default none is set and strings are parsed by strconv in base 10.
+const (
+	NumberRadix      = 1 << iota // "0x1F", "0o17", "0b101"
+	NumberUnderscore             // "1_000_000"
+	NumberThousands              // "1,234" or "1.234,5" with SetThousandsSeparator and SetDecimalSeparator
+	NumberTrimSpace              // " 42 "
+	NumberExponent               // integral float notation such as "1e3" and "12.0"
+	NumberUnits                  // SI and IEC suffixes such as "1.5k", "10MB" and "10MiB"
+)
+
+// defaultBoolValues is the boolean vocabulary used when none is set, keys are lower case.
+var defaultBoolValues = map[string]bool{
+	"1": true, "t": true, "true": true, "y": true, "yes": true, "on": true, "enabled": true,
+	"0": false, "f": false, "false": false, "n": false, "no": false, "off": false, "disabled": false, "": false,
+}
+
+// Time units of Unix timestamps, used when numbers are converted to time.Time and back.
+const (
+	TimeUnitSecond = iota // default
+	TimeUnitMilli
+	TimeUnitMicro
+	TimeUnitNano
+	TimeUnitAuto // detect the unit of input by magnitude, output uses seconds
+)
+
+// Duration styles used when a time.Duration is converted to a string.
+const (
+	DurationStyleInt    = iota // the nanoseconds as an integer like any int64, "5400000000000", default
+	DurationStyleGo            // "1h30m0s" as time.Duration.String
+	DurationStyleISO           // ISO-8601 "PT1H30M"
+	DurationStyleNumber        // a number counted in the duration unit, "5400" for time.Second
+)
+
+// Time export modes decide how times are stored when they are exported to maps and interfaces,
+// for example by a struct to map conversion.
+const (
+	TimeExportTime      = iota // keep time.Time, default
+	TimeExportString           // a string in the time format
+	TimeExportUnix             // Unix seconds
+	TimeExportUnixMilli        // Unix milliseconds
+)
+
+// Map key orders decide the order map entries are visited in when a map is converted to a list,
+// to another map or to a json string.
+const (
+	MapOrderNone    = iota // the random order of Go maps, default
+	MapOrderNatural        // numbers by value, strings lexically, by the type of the keys
+	MapOrderLexical        // by the string form of the keys, "10" before "9"
+	MapOrderNumeric        // by the numeric value of the keys, "9" before "10", other keys after numbers lexically
+)
+
+// Duplicate key policies decide which element is kept when several list elements have the same map key.
+const (
+	DuplicateLast  = iota // the last element wins, default
+	DuplicateFirst        // the first element wins
+	DuplicateError        // a duplicate key is an error
+)
+
+// Limit kinds of a LimitError.
+const (
+	LimitDepth     = iota // nesting depth, see SetMaxDepth
+	LimitInputSize        // size of a json string input, see SetMaxInputSize
+	LimitLength           // length of an input map, list or string, see SetMaxLength
+	LimitElements         // count of decoded elements, see SetMaxElements
+)
+
+// Cycle policies decide how a pointer, map or slice that holds itself, such as a node of a circular list, is decoded.
+const (
+	CycleError    = iota // a cycle is an error naming its path, default
+	CycleNil             // the reference closing the cycle is decoded as nil
+	CyclePreserve        // references to the same input are decoded to the same output, keeping cycles and shared values
+)
+
+// Time formats with special meaning, besides the layouts of the time package.
+const (
+	TimeFormatUnix       = "unix"                 // a Unix timestamp in the time unit
+	TimeFormatISOWeek    = "isoweek"              // an ISO-8601 week date such as "2024-W05-3"
+	TimeFormatISOOrdinal = "isoordinal"           // an ISO-8601 ordinal date such as "2024-032"
+	TimeFormatISOBasic   = "20060102T150405Z0700" // the ISO-8601 basic format such as "20240101T120000Z"
+)
+
 // some field can customize the parsing, such as time.Duration, net.IP, net.IPNet.
 // return DecodeStop if the hook has handled the decoding of the field.
 type HookFunc func(in interface{}, out reflect.Value) (int, error)
@@ -41,20 +157,74 @@ type HookFunc func(in interface{}, out reflect.Value) (int, error)
 type Options struct {
 	location   *time.Location //time zone default is "UTC"
 	timeFormat string         //time format default is "2006-01-02 15:04:05"
+	timeUnit   int            //unit of Unix timestamps, default is TimeUnitSecond
+
+	durationUnit  time.Duration //unit of numbers converted to time.Duration, default is time.Nanosecond
+	durationStyle int           //style of time.Duration converted to string, default is DurationStyleInt
+
+	timeLayouts []string //layouts to parse time strings in order, default is timeType
+	timeStrict  bool     //only parse time strings with timeFormat, default is false
+
+	timeNormalize bool //convert output times to location, default is false
+	timeZoneNames bool //honor IANA zone names at the end of time strings, default is false
+	timeExport    int  //how times are exported to maps and interfaces, default is TimeExportTime
+
+	relativeTime bool             //parse relative time like "now" and "3 days ago", default is false
+	clock        func() time.Time //clock of relative time, default is time.Now
 
 	mapKeyField  string //map key field,default is index
 	mapKeyToList bool   //map key to list,default is false
 
+	mapKeyFields []string //fields of composite map keys, override mapKeyField
+	mapKeySep    string   //separator of composite string map keys, default is ":"
+	mapGroup     bool     //group list elements with the same map key into a slice, default is false
+	mapDuplicate int      //policy of list elements with the same map key, default is DuplicateLast
+
+	mapEntries    bool   //convert maps to lists of key and value entries and back, default is false
+	mapEntryKey   string //key field name of map entries, default is "key"
+	mapEntryValue string //value field name of map entries, default is "value"
+
+	mapKeyOrder int                         //order of map keys, default is MapOrderNone
+	mapKeyLess  func(a, b interface{}) bool //custom order of map keys, overrides mapKeyOrder
+
 	tagName string //default is json
 
+	flatKeys bool   //convert structs to maps of flat keys and accept flat keys in maps to structs, default is false
+	flatSep  string //separator of flat keys, default is "."
+
 	exportedUnExported bool //exported lower field,default is false
 
 	structToMapDetail bool //if out is interface, convert all nest to any,default is false
 
+	useNumber bool //keep the numbers of json strings as json.Number in interfaces, default is false
+
+	cyclePolicy int //how a value holding itself is decoded, default is CycleError
+	maxDepth    int //maximum nesting depth of decoded values, default is 0 for no limit
+
+	maxInputSize int //maximum size in bytes of json string inputs, default is 0 for no limit
+	maxLength    int //maximum length of input maps, lists and strings split into chars, default is 0 for no limit
+	maxElements  int //maximum count of elements and fields decoded in one conversion, default is 0 for no limit
+
+	diffConvert bool //convert values of another type to the type of the old value when diffing, default is false
+
+	deepCopy   bool //copy the values set to interfaces instead of sharing them, default is false
+	copyShared bool //copy pointers, maps and slices referenced more than once only once, which also copies cycles, default is false
+
 	assignKey map[string]string //assign key
 
 	ignoreBasicTypeErr bool // Ignore base type error
 
+	lossyPolicy int // how lossy numeric conversions are handled, default is LossyTruncate
+
+	boolValues map[string]bool //boolean vocabulary for strings, default is defaultBoolValues
+	boolStrict bool            //only accept 0 and 1 as numeric bool, default is false
+
+	runeAsChar bool //convert int32 and uint8 as rune and byte characters to and from string, default is false
+
+	numberFormat int    //number formats accepted in numeric strings, default is none
+	thousandsSep string //thousands separator used by NumberThousands, default is ","
+	decimalSep   string //decimal separator used by NumberThousands, default is "."
+
 	hooks []HookFunc //customize the parsing
 }
 
@@ -63,7 +233,15 @@ func NewOptions() *Options {
 	return &Options{
 		location:   time.UTC,
 		timeFormat: "2006-01-02 15:04:05",
-		tagName:    "json",
+
+		durationUnit: time.Nanosecond,
+		tagName:      "json",
+
+		mapKeySep: ":",
+		flatSep:   ".",
+
+		thousandsSep: ",",
+		decimalSep:   ".",
 	}
 }
 
@@ -77,16 +255,145 @@ func (op *Options) SetTimeFormat(v string) *Options {
 	return op
 }
 
+func (op *Options) SetTimeUnit(v int) *Options {
+	op.timeUnit = v
+	return op
+}
+
+// SetTimeLayouts replaces the layouts used to parse time strings, they are tried in order.
+func (op *Options) SetTimeLayouts(v ...string) *Options {
+	op.timeLayouts = append([]string(nil), v...)
+	return op
+}
+
+// PrependTimeLayouts adds layouts tried before the current ones, for example "02/01/2006"
+// to read day first dates before any default layout.
+func (op *Options) PrependTimeLayouts(v ...string) *Options {
+	layouts := op.timeLayouts
+	if layouts == nil {
+		layouts = timeType
+	}
+	op.timeLayouts = append(append([]string(nil), v...), layouts...)
+	return op
+}
+
+func (op *Options) SetTimeStrict(v bool) *Options {
+	op.timeStrict = v
+	return op
+}
+
+// SetTimeNormalize converts every output time to the location, including times that are
+// already time.Time and strings with an offset.
+func (op *Options) SetTimeNormalize(v bool) *Options {
+	op.timeNormalize = v
+	return op
+}
+
+// SetTimeZoneNames accepts an IANA zone name after a time string, such as "2024-01-01 10:00 Asia/Shanghai",
+// the time is parsed in that zone instead of the location.
+func (op *Options) SetTimeZoneNames(v bool) *Options {
+	op.timeZoneNames = v
+	return op
+}
+
+func (op *Options) SetTimeExport(v int) *Options {
+	op.timeExport = v
+	return op
+}
+
+func (op *Options) SetDurationUnit(v time.Duration) *Options {
+	op.durationUnit = v
+	return op
+}
+
+func (op *Options) SetDurationStyle(v int) *Options {
+	op.durationStyle = v
+	return op
+}
+
+func (op *Options) SetRelativeTime(v bool) *Options {
+	op.relativeTime = v
+	return op
+}
+
+// SetClock sets the clock relative time is evaluated against, so that tests can be deterministic.
+func (op *Options) SetClock(v func() time.Time) *Options {
+	op.clock = v
+	return op
+}
+
+// SetMapKeyField keys a list converted to a map by a field of its elements, which may be a dotted path
+// such as "user.id" or a JSON pointer such as "/user/id".
 func (op *Options) SetMapKeyField(v string) *Options {
 	op.mapKeyField = v
 	return op
 }
 
+// SetMapKeyFields keys a list converted to a map by several fields. The key is a struct filled with
+// the fields, a list of their values, or for other key types their string forms joined by the separator.
+func (op *Options) SetMapKeyFields(v ...string) *Options {
+	op.mapKeyFields = append([]string(nil), v...)
+	return op
+}
+
+func (op *Options) SetMapKeySeparator(v string) *Options {
+	op.mapKeySep = v
+	return op
+}
+
+// SetMapGroup groups the list elements with the same map key, the map values must be slices such as map[K][]V.
+func (op *Options) SetMapGroup(v bool) *Options {
+	op.mapGroup = v
+	return op
+}
+
+func (op *Options) SetMapDuplicate(v int) *Options {
+	op.mapDuplicate = v
+	return op
+}
+
 func (op *Options) SetMapKeyToList(v bool) *Options {
 	op.mapKeyToList = v
 	return op
 }
 
+// SetMapEntries converts a map into a list of entries holding a key and a value, such as []MapEntry[K, V]
+// or a user struct, and a list of entries back into a map.
+func (op *Options) SetMapEntries(v bool) *Options {
+	op.mapEntries = v
+	return op
+}
+
+// SetMapEntryFields sets the names of the key and value fields of map entries, matched by tag or field name.
+func (op *Options) SetMapEntryFields(key, value string) *Options {
+	op.mapEntryKey = key
+	op.mapEntryValue = value
+	return op
+}
+
+func (op *Options) SetMapKeyOrder(v int) *Options {
+	op.mapKeyOrder = v
+	return op
+}
+
+// SetMapKeyLess orders map keys with a custom comparator, which reports whether key a is before key b.
+func (op *Options) SetMapKeyLess(v func(a, b interface{}) bool) *Options {
+	op.mapKeyLess = v
+	return op
+}
+
+// SetFlatKeys converts structs to maps of flat keys such as "db.host" and "servers[0].port",
+// and builds nested structs, lists and maps from such keys when a map is converted to a struct.
+func (op *Options) SetFlatKeys(v bool) *Options {
+	op.flatKeys = v
+	return op
+}
+
+func (op *Options) SetFlatKeySeparator(v string) *Options {
+	op.flatSep = v
+	return op
+}
+
 func (op *Options) SetTagName(v string) *Options {
 	op.tagName = v
 	return op
@@ -102,6 +409,70 @@ func (op *Options) SetStructToMapDetail(v bool) *Options {
 	return op
 }
 
+// SetUseNumber keeps the numbers of json strings that are set to interfaces as json.Number,
+// by default they are float64 like encoding/json decodes them.
+func (op *Options) SetUseNumber(v bool) *Options {
+	op.useNumber = v
+	return op
+}
+
+// SetCyclePolicy sets how a pointer, map or slice that holds itself is decoded, see CycleError.
+func (op *Options) SetCyclePolicy(v int) *Options {
+	op.cyclePolicy = v
+	return op
+}
+
+// SetMaxDepth limits the nesting depth of decoded values, deeper values are an error naming their path.
+// Zero is no limit.
+func (op *Options) SetMaxDepth(v int) *Options {
+	op.maxDepth = v
+	return op
+}
+
+// SetMaxInputSize limits the size in bytes of json strings that are decoded into maps, lists and structs.
+// Zero is no limit.
+func (op *Options) SetMaxInputSize(v int) *Options {
+	op.maxInputSize = v
+	return op
+}
+
+// SetMaxLength limits the length of input maps and lists, and of strings split into chars, it is checked
+// before an output of that length is allocated. Zero is no limit.
+func (op *Options) SetMaxLength(v int) *Options {
+	op.maxLength = v
+	return op
+}
+
+// SetMaxElements limits the count of map values, list elements and struct fields decoded in one conversion.
+// Zero is no limit.
+func (op *Options) SetMaxElements(v int) *Options {
+	op.maxElements = v
+	return op
+}
+
+// SetDiffConvert converts the new values of Diff to the type of the old values before they are compared,
+// so that a struct can be compared with a map or a string with a number.
+func (op *Options) SetDiffConvert(v bool) *Options {
+	op.diffConvert = v
+	return op
+}
+
+// SetDeepCopy copies the values that are assigned to interfaces as they are, so that maps and slices
+// in interface fields are not shared between the input and the output, see CloneE.
+func (op *Options) SetDeepCopy(v bool) *Options {
+	op.deepCopy = v
+	return op
+}
+
+// SetCopyShared copies pointers, maps and slices that are referenced more than once only once when deep
+// copying, so the copy keeps the shared references and cycles of the input instead of failing on them.
+func (op *Options) SetCopyShared(v bool) *Options {
+	op.copyShared = v
+	return op
+}
+
+// SetAssignKey assigns input keys to output fields with other names. Both may be dotted or JSON pointer
+// paths, such as "meta.created" to "CreatedAt", to read and write nested values.
 func (op *Options) SetAssignKey(v map[string]string) *Options {
 	op.assignKey = v
 	return op
@@ -112,6 +483,57 @@ func (op *Options) SetIgnoreBasicTypeErr(b bool) *Options {
 	return op
 }
 
+func (op *Options) SetLossyPolicy(v int) *Options {
+	op.lossyPolicy = v
+	return op
+}
+
+// SetBoolValues replaces the boolean vocabulary, keys are matched case-insensitively.
+func (op *Options) SetBoolValues(v map[string]bool) *Options {
+	op.boolValues = make(map[string]bool, len(v))
+	for k, b := range v {
+		op.boolValues[strings.ToLower(k)] = b
+	}
+	return op
+}
+
+// AddBoolValue extends the boolean vocabulary with a word, starting from the default vocabulary.
+func (op *Options) AddBoolValue(v string, b bool) *Options {
+	if op.boolValues == nil {
+		op.boolValues = make(map[string]bool, len(defaultBoolValues)+1)
+		for k, dv := range defaultBoolValues {
+			op.boolValues[k] = dv
+		}
+	}
+	op.boolValues[strings.ToLower(v)] = b
+	return op
+}
+
+func (op *Options) SetBoolStrict(v bool) *Options {
+	op.boolStrict = v
+	return op
+}
+
+func (op *Options) SetRuneAsChar(v bool) *Options {
+	op.runeAsChar = v
+	return op
+}
+
+func (op *Options) SetNumberFormat(v int) *Options {
+	op.numberFormat = v
+	return op
+}
+
+func (op *Options) SetThousandsSeparator(v string) *Options {
+	op.thousandsSep = v
+	return op
+}
+
+func (op *Options) SetDecimalSeparator(v string) *Options {
+	op.decimalSep = v
+	return op
+}
+
 func (op *Options) AddHook(v HookFunc) *Options {
 	op.hooks = append(op.hooks, v)
 	return op
@@ -119,12 +541,14 @@ func (op *Options) AddHook(v HookFunc) *Options {
 
 type anyClient struct {
 	options *Options
+	state   *decodeState
 }
 
 // NewAnyClient creates a new any client.
 func newAnyClient(options ...Options) *anyClient {
 	cli := &anyClient{
 		options: NewOptions(),
+		state:   &decodeState{},
 	}
 	if len(options) > 0 {
 		cli.options = &options[0]
@@ -0,0 +1,297 @@
+package goany
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/bytedance/sonic"
+)
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// sortedJSON marshals the values map keys are not ordered by, its maps are sorted lexically.
+var sortedJSON = sonic.Config{SortMapKeys: true}.Froze()
+
+// mapKeys returns the keys of a map in the map key order of the options,
+// in the random order of the map if no order is set.
+func (op *Options) mapKeys(mapVal reflect.Value) []reflect.Value {
+	keys := mapVal.MapKeys()
+	if op.mapKeyOrder == MapOrderNone && op.mapKeyLess == nil {
+		return keys
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		return op.lessMapKey(keys[i], keys[j])
+	})
+	return keys
+}
+
+// lessMapKey reports whether the map key a is ordered before b.
+func (op *Options) lessMapKey(a, b reflect.Value) bool {
+	if op.mapKeyLess != nil {
+		return op.mapKeyLess(a.Interface(), b.Interface())
+	}
+	switch op.mapKeyOrder {
+	case MapOrderLexical:
+		return keyString(a) < keyString(b)
+	case MapOrderNumeric:
+		fa, errA := toFloat64E(a.Interface(), *op)
+		fb, errB := toFloat64E(b.Interface(), *op)
+		switch {
+		case errA == nil && errB == nil && fa != fb:
+			return fa < fb
+		case errA == nil && errB != nil:
+			return true
+		case errA != nil && errB == nil:
+			return false
+		}
+		return keyString(a) < keyString(b)
+	default:
+		return naturalLess(a, b)
+	}
+}
+
+// naturalLess compares two keys by their own type: numbers by value, strings lexically and false before true.
+// Keys of different kinds are ordered by kind, keys of other kinds by their string form.
+func naturalLess(a, b reflect.Value) bool {
+	for a.Kind() == reflect.Interface && !a.IsNil() {
+		a = a.Elem()
+	}
+	for b.Kind() == reflect.Interface && !b.IsNil() {
+		b = b.Elem()
+	}
+	if a.Kind() != b.Kind() {
+		return a.Kind() < b.Kind()
+	}
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() < b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float()
+	case reflect.String:
+		return a.String() < b.String()
+	case reflect.Bool:
+		return !a.Bool() && b.Bool()
+	default:
+		return keyString(a) < keyString(b)
+	}
+}
+
+// keyString returns the string form of a map key.
+func keyString(v reflect.Value) string {
+	if s, err := toStringE(v.Interface(), *NewOptions()); err == nil {
+		return s
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// marshalJSON marshals v to json. With a map key order set, the keys of maps are written in that order,
+// also in maps nested in lists and maps, so that the output is the same between runs.
+func marshalJSON(v interface{}, op Options) ([]byte, error) {
+	if op.mapKeyOrder == MapOrderNone && op.mapKeyLess == nil {
+		return sonic.Marshal(v)
+	}
+	var buf bytes.Buffer
+	if err := writeOrderedJSON(&buf, reflect.ValueOf(v), op); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeOrderedJSON writes the json of val into buf, see marshalJSON.
+func writeOrderedJSON(buf *bytes.Buffer, val reflect.Value, op Options) error {
+	for (val.Kind() == reflect.Interface || val.Kind() == reflect.Ptr) && !val.IsNil() {
+		val = val.Elem()
+	}
+	// types with their own json encoding are marshaled as they are
+	if val.IsValid() && (val.Type().Implements(jsonMarshalerType) || val.Type().Implements(textMarshalerType)) {
+		return writeSortedJSON(buf, val)
+	}
+	if val.CanAddr() && (reflect.PtrTo(val.Type()).Implements(jsonMarshalerType) || reflect.PtrTo(val.Type()).Implements(textMarshalerType)) {
+		return writeSortedJSON(buf, val.Addr())
+	}
+	switch {
+	case val.Kind() == reflect.Map && !val.IsNil():
+		buf.WriteByte('{')
+		for i, k := range op.mapKeys(val) {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, err := sortedJSON.Marshal(keyString(k))
+			if err != nil {
+				return err
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := writeOrderedJSON(buf, val.MapIndex(k), op); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case (val.Kind() == reflect.Slice && !val.IsNil() || val.Kind() == reflect.Array) && val.Type().Elem().Kind() != reflect.Uint8:
+		buf.WriteByte('[')
+		for i := 0; i < val.Len(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeOrderedJSON(buf, val.Index(i), op); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	case val.Kind() == reflect.Struct:
+		return writeOrderedStruct(buf, addressable(val), op)
+	}
+	return writeSortedJSON(buf, val)
+}
+
+// writeOrderedStruct writes the json of a struct into buf, the fields are written by their json name
+// as encoding/json does, and their values by writeOrderedJSON so maps in fields are ordered too.
+func writeOrderedStruct(buf *bytes.Buffer, val reflect.Value, op Options) error {
+	buf.WriteByte('{')
+	n := 0
+	for _, field := range jsonFields(val.Type()) {
+		fieldVal, ok := jsonFieldValue(val, field.index)
+		if !ok || field.omitEmpty && isEmptyJSON(fieldVal) {
+			continue
+		}
+		if n > 0 {
+			buf.WriteByte(',')
+		}
+		n++
+		key, err := sortedJSON.Marshal(field.name)
+		if err != nil {
+			return err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		if err := writeOrderedJSON(buf, fieldVal, op); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// jsonField is a struct field written to json, index leads to it through the embedded structs.
+type jsonField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+// jsonFields returns the fields of a struct type written to json in their order. Untagged embedded
+// structs are promoted, and of fields with the same name the least nested one is kept.
+func jsonFields(typ reflect.Type) []jsonField {
+	var fields []jsonField
+	depths := make(map[string]int)
+	visited := make(map[reflect.Type]bool)
+	var walk func(typ reflect.Type, index []int)
+	walk = func(typ reflect.Type, index []int) {
+		if visited[typ] {
+			return
+		}
+		visited[typ] = true
+		defer delete(visited, typ)
+		for i := 0; i < typ.NumField(); i++ {
+			sf := typ.Field(i)
+			tag := sf.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			fieldIndex := append(index[:len(index):len(index)], i)
+			if sf.Anonymous && tag == "" {
+				t := sf.Type
+				if t.Kind() == reflect.Ptr {
+					t = t.Elem()
+				}
+				if t.Kind() == reflect.Struct {
+					walk(t, fieldIndex)
+					continue
+				}
+			}
+			if sf.PkgPath != "" {
+				continue
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+			if name == "" {
+				name = sf.Name
+			}
+			if depth, ok := depths[name]; ok && depth <= len(fieldIndex) {
+				continue
+			}
+			depths[name] = len(fieldIndex)
+			fields = append(fields, jsonField{name: name, index: fieldIndex, omitEmpty: strings.Contains(","+opts+",", ",omitempty,")})
+		}
+	}
+	walk(typ, nil)
+
+	// drop the deeper fields hidden by a field found later at a lower depth
+	out := fields[:0]
+	for _, field := range fields {
+		if depths[field.name] == len(field.index) {
+			out = append(out, field)
+		}
+	}
+	return out
+}
+
+// jsonFieldValue returns the field of val at index, false if an embedded pointer on the way is nil.
+func jsonFieldValue(val reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 {
+			if val.Kind() == reflect.Ptr {
+				if val.IsNil() {
+					return reflect.Value{}, false
+				}
+				val = val.Elem()
+			}
+		}
+		field := val.Type().Field(x)
+		val = val.Field(x)
+		if field.PkgPath != "" {
+			val = unexportedField(val) // fields promoted from an unexported embedded struct
+		}
+	}
+	return val, true
+}
+
+// isEmptyJSON reports whether a field tagged omitempty is left out of the json, as in encoding/json.
+func isEmptyJSON(val reflect.Value) bool {
+	switch val.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return val.Len() == 0
+	case reflect.Bool:
+		return !val.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return val.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return val.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return val.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return val.IsNil()
+	}
+	return false
+}
+
+// writeSortedJSON writes the json of val into buf with the maps sorted lexically.
+func writeSortedJSON(buf *bytes.Buffer, val reflect.Value) error {
+	var v interface{}
+	if val.IsValid() && val.CanInterface() {
+		v = val.Interface()
+	}
+	b, err := sortedJSON.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.Write(b)
+	return nil
+}
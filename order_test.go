@@ -0,0 +1,114 @@
+package goany
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapKeyOrder(t *testing.T) {
+	in := map[string]int{"10": 10, "9": 9, "b": 2, "a": 1, "100": 100}
+
+	tests := []struct {
+		name     string
+		op       *Options
+		expected []int
+	}{
+		{name: "Test with natural", op: NewOptions().SetMapKeyOrder(MapOrderNatural), expected: []int{10, 100, 9, 1, 2}},
+		{name: "Test with lexical", op: NewOptions().SetMapKeyOrder(MapOrderLexical), expected: []int{10, 100, 9, 1, 2}},
+		{name: "Test with numeric", op: NewOptions().SetMapKeyOrder(MapOrderNumeric), expected: []int{9, 10, 100, 1, 2}},
+		{
+			name: "Test with custom",
+			op: NewOptions().SetMapKeyLess(func(a, b interface{}) bool {
+				return a.(string) > b.(string)
+			}),
+			expected: []int{2, 1, 9, 100, 10},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 5; i++ {
+				var out []int
+				err := ToAny(in, &out, *tt.op)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, out)
+			}
+		})
+	}
+
+	t.Run("Test with natural numbers", func(t *testing.T) {
+		var out []string
+		op := NewOptions().SetMapKeyOrder(MapOrderNatural).SetMapKeyToList(true)
+		err := ToAny(map[int]bool{10: true, -1: true, 2: true}, &out, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"-1", "2", "10"}, out)
+
+		err = ToAny(map[int]bool{10: true, -1: true, 2: true}, &out, *op.SetMapKeyOrder(MapOrderLexical))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"-1", "10", "2"}, out)
+	})
+
+	t.Run("Test with string output", func(t *testing.T) {
+		nested := map[string]interface{}{
+			"z": 1,
+			"a": []interface{}{map[int]string{2: "b", 1: "a"}},
+			"m": map[string]interface{}{"y": true, "x": nil},
+			"s": struct {
+				Tags map[string]int `json:"tags"`
+			}{Tags: map[string]int{"q": 1, "p": 2}},
+		}
+		op := NewOptions().SetMapKeyOrder(MapOrderNatural)
+		for i := 0; i < 5; i++ {
+			s, err := ToStringE(nested, *op)
+			assert.NoError(t, err)
+			assert.Equal(t, `{"a":[{"1":"a","2":"b"}],"m":{"x":null,"y":true},"s":{"tags":{"p":2,"q":1}},"z":1}`, s)
+		}
+
+		type Base struct {
+			ID    int            `json:"id"`
+			Attrs map[string]int `json:"attrs,omitempty"`
+		}
+		type item struct {
+			Base
+			Counts map[string]int `json:"counts"`
+			Note   string         `json:"note,omitempty"`
+			Skip   string         `json:"-"`
+			secret string
+			Since  time.Time `json:"since"`
+		}
+		numeric := NewOptions().SetMapKeyOrder(MapOrderNumeric)
+		in := item{Base: Base{ID: 1}, Counts: map[string]int{"10": 1, "9": 2, "100": 3}, Skip: "x", secret: "y"}
+		for i := 0; i < 5; i++ {
+			s, err := ToStringE(&in, *numeric)
+			assert.NoError(t, err)
+			assert.Equal(t, `{"id":1,"counts":{"9":2,"10":1,"100":3},"since":"0001-01-01T00:00:00Z"}`, s)
+		}
+
+		s, err := ToStringE(map[string]string{"k\"ey": "<v>"}, *op)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(s, `{"k\"ey":`), s)
+	})
+
+	t.Run("Test with map to map", func(t *testing.T) {
+		var visited []interface{}
+		op := NewOptions().SetMapKeyOrder(MapOrderNumeric).AddHook(func(in interface{}, out reflect.Value) (int, error) {
+			if out.Kind() == reflect.Int {
+				visited = append(visited, in)
+			}
+			return DecodeContinue, nil
+		})
+		var out map[string]int
+		err := ToAny(map[string]string{"10": "c", "2": "b", "1": "a"}, &out, *op)
+		assert.Error(t, err)
+		assert.Equal(t, []interface{}{"a"}, visited)
+
+		visited = nil
+		err = ToAny(map[string]string{"10": "10", "2": "2", "1": "1"}, &out, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int{"1": 1, "2": 2, "10": 10}, out)
+		assert.Equal(t, []interface{}{"1", "2", "10"}, visited)
+	})
+}
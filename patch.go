@@ -0,0 +1,524 @@
+package goany
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/pkg/errors"
+)
+
+// Operations of a JSON patch (RFC 6902).
+const (
+	PatchAdd     = "add"
+	PatchRemove  = "remove"
+	PatchReplace = "replace"
+	PatchMove    = "move"
+	PatchCopy    = "copy"
+	PatchTest    = "test"
+)
+
+// pointerEscaper encodes the segments of JSON pointers.
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// PatchOperation is an operation of a JSON patch (RFC 6902). Path and From are JSON pointers, such as
+// "/servers/0/port", dotted paths such as "servers[0].port" are accepted too. Value is always written,
+// so that a null value of add, replace and test is kept.
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value"`
+}
+
+// ApplyPatch applies a patch to the value target points to. The patch is a JSON patch (RFC 6902),
+// a []PatchOperation or a list of operations, or a JSON merge patch (RFC 7386), a map, or either of
+// them as a json string or bytes. Paths are resolved through tag names like Get, and patch values are
+// converted to the types of the values they are set to. The patch is applied to a copy of the target,
+// so the target is only changed if all operations succeed.
+func ApplyPatch(target interface{}, patch interface{}, op ...Options) error {
+	targetVal := reflect.ValueOf(target)
+	if targetVal.Kind() != reflect.Ptr || targetVal.IsNil() {
+		return errors.Errorf(ErrUnSupportType, target)
+	}
+	patch, parsed, err := parsePatch(patch)
+	if err != nil {
+		return err
+	}
+
+	cli := newAnyClient(op...)
+	if parsed {
+		cli.state.jsonNumbers++ // numbers set to interfaces are float64, as from other json strings
+	}
+	copyOptions := *cli.options
+	copyOptions.copyShared = true
+	doc, err := (&anyClient{options: &copyOptions, state: cli.state}).deepCopy(targetVal.Elem())
+	if err != nil {
+		return err
+	}
+	out := reflect.New(doc.Type()).Elem()
+	out.Set(doc)
+
+	patchVal := reflect.Indirect(reflect.ValueOf(patch))
+	switch patchVal.Kind() {
+	case reflect.Map:
+		err = cli.mergePatch(out, patchVal)
+	case reflect.Slice, reflect.Array:
+		var ops []PatchOperation
+		if ops, err = patchOperations(patch); err == nil {
+			err = cli.applyOperations(out, ops)
+		}
+	default:
+		err = errors.Errorf(ErrNotPatch, patch)
+	}
+	if err != nil {
+		return err
+	}
+	targetVal.Elem().Set(out)
+	return nil
+}
+
+// CreatePatch returns the JSON patch (RFC 6902) that changes a into b, see DiffE for how they are compared.
+func CreatePatch(a, b interface{}, op ...Options) ([]PatchOperation, error) {
+	changes, err := diffValues(a, b, op...)
+	if err != nil {
+		return nil, err
+	}
+	ops := make([]PatchOperation, len(changes))
+	for i, c := range changes {
+		ops[i] = PatchOperation{Op: c.Kind, Path: pointerOf(c.segments), Value: c.New}
+	}
+	return ops, nil
+}
+
+// parsePatch decodes a patch in a json string or bytes and reports whether it did, other patches are
+// returned as they are. Numbers are kept as json.Number so that integers do not lose precision.
+func parsePatch(patch interface{}) (interface{}, bool, error) {
+	var data []byte
+	switch p := patch.(type) {
+	case string:
+		data = []byte(p)
+	case []byte:
+		data = p
+	default:
+		return patch, false, nil
+	}
+	out, err := decodeJSON(data)
+	if err != nil {
+		return nil, false, errors.Errorf(ErrNotPatch, patch)
+	}
+	return out, true, nil
+}
+
+// decodeJSON decodes json data into maps, lists and basic values, numbers are json.Number.
+func decodeJSON(data []byte) (interface{}, error) {
+	if !json.Valid(data) {
+		return nil, errors.Errorf(ErrNotJson, string(data))
+	}
+	var out interface{}
+	dec := sonic.ConfigDefault.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// patchOperations converts a list of operations, such as maps decoded from json, into patch operations.
+func patchOperations(patch interface{}) ([]PatchOperation, error) {
+	if ops, ok := patch.([]PatchOperation); ok {
+		return ops, nil
+	}
+	var ops []PatchOperation
+	if err := ToAny(patch, &ops); err != nil {
+		return nil, err
+	}
+	return ops, nil
+}
+
+// pointerOf formats path segments as a JSON pointer.
+func pointerOf(segments []flatSegment) string {
+	var b strings.Builder
+	for _, segment := range segments {
+		b.WriteString("/")
+		b.WriteString(pointerEscaper.Replace(segment.String()))
+	}
+	return b.String()
+}
+
+// applyOperations applies the operations of a JSON patch to the settable doc in order.
+func (cli *anyClient) applyOperations(doc reflect.Value, ops []PatchOperation) error {
+	for _, operation := range ops {
+		var err error
+		switch operation.Op {
+		case PatchAdd:
+			err = cli.patchAdd(doc, operation.Path, operation.Value)
+		case PatchRemove:
+			_, err = cli.patchRemove(doc, operation.Path)
+		case PatchReplace:
+			err = cli.patchReplace(doc, operation.Path, operation.Value)
+		case PatchMove:
+			var v interface{}
+			if v, err = cli.patchRemove(doc, operation.From); err == nil {
+				err = cli.patchAdd(doc, operation.Path, v)
+			}
+		case PatchCopy:
+			var v interface{}
+			if v, err = cli.patchGet(doc, operation.From); err == nil {
+				err = cli.patchAdd(doc, operation.Path, v)
+			}
+		case PatchTest:
+			err = cli.patchTest(doc, operation.Path, operation.Value)
+		default:
+			err = errors.Errorf(ErrPatchOp, operation.Op)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// patchGet returns a deep copy of the value at path.
+func (cli *anyClient) patchGet(doc reflect.Value, path string) (interface{}, error) {
+	v, ok := cli.lookupPath(doc, pathKeys(path))
+	if !ok || !v.CanInterface() {
+		return nil, errors.Errorf(ErrPathNotFound, path)
+	}
+	copied, err := cli.deepCopy(v)
+	if err != nil {
+		return nil, err
+	}
+	return copied.Interface(), nil
+}
+
+// patchTest compares the value at path with v as json values (RFC 6902 section 4.6): both are
+// marshaled to json, and they must be of the same json type and equal, numbers by their value.
+func (cli *anyClient) patchTest(doc reflect.Value, path string, v interface{}) error {
+	current, err := cli.patchGet(doc, path)
+	if err != nil {
+		return err
+	}
+	a, errA := jsonValue(current)
+	b, errB := jsonValue(v)
+	if errA != nil || errB != nil || !jsonEqual(a, b) {
+		return errors.Errorf(ErrPatchTest, path)
+	}
+	return nil
+}
+
+// jsonValue returns the value of the json of v, see decodeJSON.
+func jsonValue(v interface{}) (interface{}, error) {
+	data, err := sonic.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSON(data)
+}
+
+// jsonEqual reports whether two values of decodeJSON are equal json values. Objects are equal with the
+// same members, arrays with the same elements in order, and numbers with the same value.
+func jsonEqual(a, b interface{}) bool {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, v := range a {
+			if w, ok := b[k]; !ok || !jsonEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !jsonEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		// integers are compared exactly, other numbers as float64
+		if x, err := a.Int64(); err == nil {
+			if y, err := b.Int64(); err == nil {
+				return x == y
+			}
+		}
+		x, errX := a.Float64()
+		y, errY := b.Float64()
+		return errX == nil && errY == nil && x == y
+	default:
+		return a == b
+	}
+}
+
+// patchAdd sets v at path, a list element is inserted at its index, or appended with the index "-".
+func (cli *anyClient) patchAdd(doc reflect.Value, path string, v interface{}) error {
+	keys := pathKeys(path)
+	if len(keys) == 0 {
+		return cli.decodeFresh(v, doc)
+	}
+	return cli.patchParent(doc, keys, path, func(parent reflect.Value, key string) error {
+		if parent.Kind() != reflect.Slice {
+			return cli.patchSet(parent, key, path, v, false)
+		}
+		i := parent.Len()
+		if key != "-" {
+			var err error
+			if i, err = strconv.Atoi(key); err != nil || i < 0 || i > parent.Len() {
+				return errors.Errorf(ErrPathNotFound, path)
+			}
+		}
+		elem := reflect.New(parent.Type().Elem()).Elem()
+		if err := cli.decodeAny(v, elem); err != nil {
+			return err
+		}
+		out := reflect.MakeSlice(parent.Type(), 0, parent.Len()+1)
+		out = reflect.AppendSlice(out, parent.Slice(0, i))
+		out = reflect.Append(out, elem)
+		out = reflect.AppendSlice(out, parent.Slice(i, parent.Len()))
+		parent.Set(out)
+		return nil
+	})
+}
+
+// patchReplace sets v at path, which must exist.
+func (cli *anyClient) patchReplace(doc reflect.Value, path string, v interface{}) error {
+	keys := pathKeys(path)
+	if len(keys) == 0 {
+		return cli.decodeFresh(v, doc)
+	}
+	return cli.patchParent(doc, keys, path, func(parent reflect.Value, key string) error {
+		return cli.patchSet(parent, key, path, v, true)
+	})
+}
+
+// patchRemove removes the value at path and returns it. A list element is removed from the list,
+// a map key from the map, and a struct field is reset to its zero value.
+func (cli *anyClient) patchRemove(doc reflect.Value, path string) (interface{}, error) {
+	removed, err := cli.patchGet(doc, path)
+	if err != nil {
+		return nil, err
+	}
+	keys := pathKeys(path)
+	if len(keys) == 0 {
+		doc.Set(reflect.Zero(doc.Type()))
+		return removed, nil
+	}
+	return removed, cli.patchParent(doc, keys, path, func(parent reflect.Value, key string) error {
+		switch parent.Kind() {
+		case reflect.Struct:
+			field, ok := cli.structField(parent, key, true)
+			if !ok || !field.CanSet() {
+				return errors.Errorf(ErrPathNotFound, path)
+			}
+			field.Set(reflect.Zero(field.Type()))
+		case reflect.Map:
+			k, _ := cli.pathMapKey(parent.Type().Key(), key)
+			parent.SetMapIndex(k, reflect.Value{})
+		case reflect.Slice:
+			i, _ := strconv.Atoi(key)
+			out := reflect.MakeSlice(parent.Type(), 0, parent.Len()-1)
+			out = reflect.AppendSlice(out, parent.Slice(0, i))
+			out = reflect.AppendSlice(out, parent.Slice(i+1, parent.Len()))
+			parent.Set(out)
+		default:
+			return errors.Errorf(ErrPathNotSettable, path)
+		}
+		return nil
+	})
+}
+
+// patchSet decodes v into the struct field, map value or list element key of parent. If mustExist is set,
+// a map key that is not in the map is an error.
+func (cli *anyClient) patchSet(parent reflect.Value, key, path string, v interface{}, mustExist bool) error {
+	switch parent.Kind() {
+	case reflect.Struct:
+		field, ok := cli.structField(parent, key, true)
+		if !ok || !field.CanSet() {
+			return errors.Errorf(ErrPathNotFound, path)
+		}
+		return cli.decodeFresh(v, field)
+	case reflect.Map:
+		k, ok := cli.pathMapKey(parent.Type().Key(), key)
+		if !ok || (mustExist && !parent.MapIndex(k).IsValid()) {
+			return errors.Errorf(ErrPathNotFound, path)
+		}
+		elem := reflect.New(parent.Type().Elem()).Elem()
+		if err := cli.decodeAny(v, elem); err != nil {
+			return err
+		}
+		if parent.IsNil() {
+			parent.Set(reflect.MakeMap(parent.Type()))
+		}
+		parent.SetMapIndex(k, elem)
+		return nil
+	case reflect.Slice, reflect.Array:
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= parent.Len() {
+			return errors.Errorf(ErrPathNotFound, path)
+		}
+		return cli.decodeFresh(v, parent.Index(i))
+	default:
+		return errors.Errorf(ErrPathNotSettable, path)
+	}
+}
+
+// decodeFresh decodes v into a zero value of the type of outVal and sets it, so that nothing is kept
+// from the value it replaces.
+func (cli *anyClient) decodeFresh(v interface{}, outVal reflect.Value) error {
+	fresh := reflect.New(outVal.Type()).Elem()
+	if err := cli.decodeAny(v, fresh); err != nil {
+		return err
+	}
+	outVal.Set(fresh)
+	return nil
+}
+
+// patchParent walks the settable val to the container of the last key and calls apply with it.
+// Map values and interfaces are not addressable, they are copied on the way and set back after.
+func (cli *anyClient) patchParent(val reflect.Value, keys []string, path string, apply func(parent reflect.Value, key string) error) error {
+	switch val.Kind() {
+	case reflect.Ptr:
+		if val.IsNil() {
+			return errors.Errorf(ErrPathNotFound, path)
+		}
+		return cli.patchParent(val.Elem(), keys, path, apply)
+	case reflect.Interface:
+		if val.IsNil() {
+			return errors.Errorf(ErrPathNotFound, path)
+		}
+		child := reflect.New(val.Elem().Type()).Elem()
+		child.Set(val.Elem())
+		if err := cli.patchParent(child, keys, path, apply); err != nil {
+			return err
+		}
+		val.Set(child)
+		return nil
+	}
+	if len(keys) == 1 {
+		return apply(val, keys[0])
+	}
+
+	switch val.Kind() {
+	case reflect.Struct:
+		field, ok := cli.structField(val, keys[0], true)
+		if !ok || !field.CanSet() {
+			return errors.Errorf(ErrPathNotFound, path)
+		}
+		return cli.patchParent(field, keys[1:], path, apply)
+	case reflect.Map:
+		k, ok := cli.pathMapKey(val.Type().Key(), keys[0])
+		if !ok || !val.MapIndex(k).IsValid() {
+			return errors.Errorf(ErrPathNotFound, path)
+		}
+		child := reflect.New(val.Type().Elem()).Elem()
+		child.Set(val.MapIndex(k))
+		if err := cli.patchParent(child, keys[1:], path, apply); err != nil {
+			return err
+		}
+		val.SetMapIndex(k, child)
+		return nil
+	case reflect.Slice, reflect.Array:
+		i, err := strconv.Atoi(keys[0])
+		if err != nil || i < 0 || i >= val.Len() {
+			return errors.Errorf(ErrPathNotFound, path)
+		}
+		return cli.patchParent(val.Index(i), keys[1:], path, apply)
+	default:
+		return errors.Errorf(ErrPathNotFound, path)
+	}
+}
+
+// mergePatch applies a JSON merge patch (RFC 7386) to the settable val. Null values remove map keys and
+// reset struct fields, object values are merged into the values they patch, other values replace them.
+func (cli *anyClient) mergePatch(val reflect.Value, patch reflect.Value) error {
+	switch val.Kind() {
+	case reflect.Ptr:
+		if val.IsNil() {
+			val.Set(reflect.New(val.Type().Elem()))
+		}
+		return cli.mergePatch(val.Elem(), patch)
+	case reflect.Interface:
+		child := reflect.ValueOf(&map[string]interface{}{}).Elem()
+		if !val.IsNil() && (val.Elem().Kind() == reflect.Map || val.Elem().Kind() == reflect.Struct) {
+			child = reflect.New(val.Elem().Type()).Elem()
+			child.Set(val.Elem())
+		}
+		if err := cli.mergePatch(child, patch); err != nil {
+			return err
+		}
+		val.Set(child)
+		return nil
+	case reflect.Struct, reflect.Map:
+	default:
+		return errors.Errorf(ErrInToOut, patch.Interface(), val.Type().String())
+	}
+
+	iter := patch.MapRange()
+	for iter.Next() {
+		key, err := toStringE(iter.Key().Interface(), *cli.options)
+		if err != nil {
+			return err
+		}
+		v := diffIndirect(iter.Value())
+		if val.Kind() == reflect.Struct {
+			field, ok := cli.structField(val, key, true)
+			if !ok || !field.CanSet() {
+				continue // like mapToStruct, keys without a field are ignored
+			}
+			switch {
+			case !v.IsValid():
+				field.Set(reflect.Zero(field.Type()))
+			case v.Kind() == reflect.Map:
+				err = cli.mergePatch(field, v)
+			default:
+				err = cli.decodeFresh(v.Interface(), field)
+			}
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		k, ok := cli.pathMapKey(val.Type().Key(), key)
+		if !ok {
+			return errors.Errorf(ErrPathNotSettable, key)
+		}
+		if !v.IsValid() {
+			if !val.IsNil() {
+				val.SetMapIndex(k, reflect.Value{})
+			}
+			continue
+		}
+		elem := reflect.New(val.Type().Elem()).Elem()
+		if existing := val.MapIndex(k); existing.IsValid() && v.Kind() == reflect.Map {
+			elem.Set(existing)
+			err = cli.mergePatch(elem, v)
+		} else if v.Kind() == reflect.Map {
+			err = cli.mergePatch(elem, v)
+		} else {
+			err = cli.decodeAny(v.Interface(), elem)
+		}
+		if err != nil {
+			return err
+		}
+		if val.IsNil() {
+			val.Set(reflect.MakeMap(val.Type()))
+		}
+		val.SetMapIndex(k, elem)
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package goany
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type patchServer struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+}
+
+type patchConfig struct {
+	Name    string                 `json:"name"`
+	Servers []patchServer          `json:"servers"`
+	Labels  map[string]string      `json:"labels"`
+	Backup  *patchServer           `json:"backup"`
+	Extra   map[string]interface{} `json:"extra"`
+}
+
+func newPatchConfig() patchConfig {
+	return patchConfig{
+		Name:    "app",
+		Servers: []patchServer{{Host: "a", Port: 80}, {Host: "b", Port: 81}},
+		Labels:  map[string]string{"team": "core"},
+		Extra:   map[string]interface{}{"n": 1.0, "list": []interface{}{"x"}},
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	t.Run("Test with json patch", func(t *testing.T) {
+		config := newPatchConfig()
+		patch := `[
+			{"op": "replace", "path": "/name", "value": "web"},
+			{"op": "add", "path": "/servers/1", "value": {"host": "c", "port": "82"}},
+			{"op": "add", "path": "/servers/-", "value": {"host": "d"}},
+			{"op": "remove", "path": "/servers/0"},
+			{"op": "test", "path": "/servers/0/port", "value": 82.0},
+			{"op": "add", "path": "/labels/zone", "value": "eu"},
+			{"op": "move", "from": "/labels/team", "path": "/labels/owner"},
+			{"op": "copy", "from": "/servers/1", "path": "/backup"},
+			{"op": "add", "path": "/extra/list/0", "value": "w"},
+			{"op": "replace", "path": "/extra/n", "value": "two"}
+		]`
+		assert.NoError(t, ApplyPatch(&config, patch))
+		assert.Equal(t, patchConfig{
+			Name:    "web",
+			Servers: []patchServer{{Host: "c", Port: 82}, {Host: "b", Port: 81}, {Host: "d"}},
+			Labels:  map[string]string{"zone": "eu", "owner": "core"},
+			Backup:  &patchServer{Host: "b", Port: 81},
+			Extra:   map[string]interface{}{"n": "two", "list": []interface{}{"w", "x"}},
+		}, config)
+	})
+
+	t.Run("Test with operations", func(t *testing.T) {
+		config := newPatchConfig()
+		ops := []PatchOperation{
+			{Op: PatchReplace, Path: "servers[1].port", Value: 8081},
+			{Op: PatchRemove, Path: "/labels/team"},
+			{Op: PatchRemove, Path: "/name"},
+		}
+		assert.NoError(t, ApplyPatch(&config, ops))
+		assert.Equal(t, 8081, config.Servers[1].Port)
+		assert.Empty(t, config.Labels)
+		assert.Equal(t, "", config.Name)
+
+		var m map[string]interface{}
+		assert.NoError(t, ApplyPatch(&m, []map[string]interface{}{{"op": "add", "path": "", "value": map[string]interface{}{"a": 1}}}))
+		assert.Equal(t, map[string]interface{}{"a": 1}, m)
+	})
+
+	t.Run("Test with errors", func(t *testing.T) {
+		config := newPatchConfig()
+		tests := []struct {
+			patch    string
+			expected string
+		}{
+			{patch: `[{"op": "replace", "path": "/missing", "value": 1}]`, expected: errors.Errorf(ErrPathNotFound, "/missing").Error()},
+			{patch: `[{"op": "replace", "path": "/labels/none", "value": 1}]`, expected: errors.Errorf(ErrPathNotFound, "/labels/none").Error()},
+			{patch: `[{"op": "add", "path": "/servers/5", "value": {}}]`, expected: errors.Errorf(ErrPathNotFound, "/servers/5").Error()},
+			{patch: `[{"op": "remove", "path": "/servers/2"}]`, expected: errors.Errorf(ErrPathNotFound, "/servers/2").Error()},
+			{patch: `[{"op": "test", "path": "/name", "value": "web"}]`, expected: errors.Errorf(ErrPatchTest, "/name").Error()},
+			{patch: `[{"op": "rename", "path": "/name"}]`, expected: errors.Errorf(ErrPatchOp, "rename").Error()},
+			{patch: `"name"`, expected: errors.Errorf(ErrNotPatch, "name").Error()},
+			{patch: `[{"op": "replace", "path": "/name", "value": "web"}, {"op": "remove", "path": "/nothing"}]`, expected: errors.Errorf(ErrPathNotFound, "/nothing").Error()},
+		}
+		for _, tt := range tests {
+			err := ApplyPatch(&config, tt.patch)
+			if assert.Error(t, err, tt.patch) {
+				assert.Equal(t, tt.expected, err.Error(), tt.patch)
+			}
+		}
+		assert.Equal(t, newPatchConfig(), config)
+
+		assert.Error(t, ApplyPatch(config, "[]"))
+		assert.Error(t, ApplyPatch(&config, `[{"op": "add", "path": "/servers/0", "value": "x"}]`))
+	})
+
+	t.Run("Test with json types", func(t *testing.T) {
+		config := newPatchConfig()
+		tests := []struct {
+			patch string
+			ok    bool
+		}{
+			{patch: `[{"op": "test", "path": "/servers/0/port", "value": 80}]`, ok: true},
+			{patch: `[{"op": "test", "path": "/servers/0/port", "value": "80"}]`},
+			{patch: `[{"op": "test", "path": "/servers/0", "value": {"host": "a", "port": 80}}]`, ok: true},
+			{patch: `[{"op": "test", "path": "/servers/0", "value": {"host": "a", "port": "80"}}]`},
+			{patch: `[{"op": "test", "path": "/servers/0", "value": {"host": "a"}}]`},
+			{patch: `[{"op": "test", "path": "/extra/list", "value": ["x"]}]`, ok: true},
+			{patch: `[{"op": "test", "path": "/backup", "value": null}]`, ok: true},
+			{patch: `[{"op": "test", "path": "/name", "value": null}]`},
+		}
+		for _, tt := range tests {
+			err := ApplyPatch(&config, tt.patch)
+			if tt.ok {
+				assert.NoError(t, err, tt.patch)
+			} else {
+				assert.Error(t, err, tt.patch)
+			}
+		}
+	})
+
+	t.Run("Test with large integers", func(t *testing.T) {
+		type counter struct {
+			ID    int64                  `json:"id"`
+			Extra map[string]interface{} `json:"extra"`
+		}
+		var c counter
+		assert.NoError(t, ApplyPatch(&c, `[{"op": "add", "path": "/id", "value": 9007199254740993}]`))
+		assert.Equal(t, int64(9007199254740993), c.ID)
+		assert.NoError(t, ApplyPatch(&c, `[{"op": "test", "path": "/id", "value": 9007199254740993}]`))
+		assert.Error(t, ApplyPatch(&c, `[{"op": "test", "path": "/id", "value": 9007199254740992}]`))
+
+		assert.NoError(t, ApplyPatch(&c, `{"id": 9007199254740995, "extra": {"n": 1}}`))
+		assert.Equal(t, counter{ID: 9007199254740995, Extra: map[string]interface{}{"n": 1.0}}, c)
+
+		assert.NoError(t, ApplyPatch(&c, `{"extra": {"n": 2}}`, *NewOptions().SetUseNumber(true)))
+		assert.Equal(t, json.Number("2"), c.Extra["n"])
+	})
+}
+
+func TestPatchFields(t *testing.T) {
+	type user struct {
+		Name     string `json:"name"`
+		IsAdmin  bool   `json:"-"`
+		PassHash string `json:"pass_hash"`
+	}
+
+	t.Run("Test with ignored and go names", func(t *testing.T) {
+		u := user{Name: "ann"}
+		assert.NoError(t, ApplyPatch(&u, `{"isadmin": true, "IsAdmin": true, "passhash": "x", "PassHash": "x", "-": true}`))
+		assert.Equal(t, user{Name: "ann"}, u)
+
+		for _, path := range []string{"/IsAdmin", "/isadmin", "/-", "/PassHash"} {
+			err := ApplyPatch(&u, []PatchOperation{{Op: PatchReplace, Path: path, Value: true}})
+			assert.Equal(t, errors.Errorf(ErrPathNotFound, path).Error(), err.Error(), path)
+		}
+		assert.Equal(t, user{Name: "ann"}, u)
+	})
+
+	t.Run("Test with embedded round trip", func(t *testing.T) {
+		a := diffConfig{DiffBase: DiffBase{Env: "dev"}, Name: "app", Labels: map[string]string{"team": "core"}}
+		b := diffConfig{DiffBase: DiffBase{Env: "prod"}, Name: "web", Labels: map[string]string{"team": "core"}}
+		ops, err := CreatePatch(a, b)
+		assert.NoError(t, err)
+		assert.Equal(t, []PatchOperation{{Op: PatchReplace, Path: "/env", Value: "prod"}, {Op: PatchReplace, Path: "/name", Value: "web"}}, ops)
+		assert.NoError(t, ApplyPatch(&a, ops))
+		assert.Equal(t, b, a)
+
+		assert.NoError(t, ApplyPatch(&a, `{"env": "test"}`))
+		assert.Equal(t, "test", a.Env)
+	})
+}
+
+func TestMergePatch(t *testing.T) {
+	t.Run("Test with struct", func(t *testing.T) {
+		config := newPatchConfig()
+		patch := `{"name": "web", "labels": {"team": null, "zone": "eu"}, "backup": {"host": "bk"}, "extra": {"n": null, "m": {"k": 1}}, "unknown": 1}`
+		assert.NoError(t, ApplyPatch(&config, patch))
+		assert.Equal(t, patchConfig{
+			Name:    "web",
+			Servers: newPatchConfig().Servers,
+			Labels:  map[string]string{"zone": "eu"},
+			Backup:  &patchServer{Host: "bk"},
+			Extra:   map[string]interface{}{"list": []interface{}{"x"}, "m": map[string]interface{}{"k": 1.0}},
+		}, config)
+
+		assert.NoError(t, ApplyPatch(&config, map[string]interface{}{"backup": map[string]interface{}{"port": "9"}, "servers": nil}))
+		assert.Equal(t, &patchServer{Host: "bk", Port: 9}, config.Backup)
+		assert.Nil(t, config.Servers)
+	})
+
+	t.Run("Test with map", func(t *testing.T) {
+		doc := map[string]interface{}{"a": "b", "c": map[string]interface{}{"d": "e", "f": "g"}}
+		assert.NoError(t, ApplyPatch(&doc, []byte(`{"a": "z", "c": {"f": null}, "h": [1]}`)))
+		assert.Equal(t, map[string]interface{}{"a": "z", "c": map[string]interface{}{"d": "e"}, "h": []interface{}{1.0}}, doc)
+
+		var nested map[string]map[string]int
+		assert.NoError(t, ApplyPatch(&nested, map[string]interface{}{"x": map[string]interface{}{"y": "1"}}))
+		assert.Equal(t, map[string]map[string]int{"x": {"y": 1}}, nested)
+
+		assert.Error(t, ApplyPatch(&nested, map[string]interface{}{"x": map[string]interface{}{"y": "one"}}))
+		assert.Equal(t, map[string]map[string]int{"x": {"y": 1}}, nested)
+	})
+}
+
+func TestCreatePatch(t *testing.T) {
+	t.Run("Test with round trip", func(t *testing.T) {
+		a := newPatchConfig()
+		b := newPatchConfig()
+		b.Name = "web"
+		b.Servers = b.Servers[:1]
+		b.Labels = map[string]string{"a/b": "x"}
+		b.Backup = &patchServer{Host: "bk"}
+		b.Extra["list"] = []interface{}{"x", "y", "z"}
+
+		ops, err := CreatePatch(a, b)
+		assert.NoError(t, err)
+		assert.Equal(t, []PatchOperation{
+			{Op: PatchReplace, Path: "/name", Value: "web"},
+			{Op: PatchRemove, Path: "/servers/1"},
+			{Op: PatchAdd, Path: "/labels/a~1b", Value: "x"},
+			{Op: PatchRemove, Path: "/labels/team"},
+			{Op: PatchReplace, Path: "/backup", Value: patchServer{Host: "bk"}},
+			{Op: PatchAdd, Path: "/extra/list/1", Value: "y"},
+			{Op: PatchAdd, Path: "/extra/list/2", Value: "z"},
+		}, ops)
+
+		assert.NoError(t, ApplyPatch(&a, ops))
+		assert.Equal(t, b, a)
+
+		data, err := ToStringE(ops)
+		assert.NoError(t, err)
+		c := newPatchConfig()
+		assert.NoError(t, ApplyPatch(&c, data))
+		assert.Equal(t, b, c)
+	})
+
+	t.Run("Test with null values", func(t *testing.T) {
+		a := map[string]interface{}{"a": 1.0, "b": nil}
+		b := map[string]interface{}{"a": nil, "b": nil, "c": nil}
+		ops, err := CreatePatch(a, b)
+		assert.NoError(t, err)
+		data, err := ToStringE(ops)
+		assert.NoError(t, err)
+		assert.Equal(t, `[{"op":"replace","path":"/a","value":null},{"op":"add","path":"/c","value":null}]`, data)
+		assert.NoError(t, ApplyPatch(&a, data))
+		assert.Equal(t, b, a)
+	})
+
+	t.Run("Test with removed list tail", func(t *testing.T) {
+		a := []int{1, 2, 3, 4}
+		ops, err := CreatePatch(a, []int{1})
+		assert.NoError(t, err)
+		assert.Equal(t, []PatchOperation{{Op: PatchRemove, Path: "/3"}, {Op: PatchRemove, Path: "/2"}, {Op: PatchRemove, Path: "/1"}}, ops)
+		assert.NoError(t, ApplyPatch(&a, ops))
+		assert.Equal(t, []int{1}, a)
+	})
+}
@@ -0,0 +1,321 @@
+package goany
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// maxSetGrowth is the number of elements Set adds to a list at most when the maxLength option is not set.
+const maxSetGrowth = 1024
+
+// pointerUnescaper decodes the escapes of JSON pointer segments.
+var pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+// isPath reports whether a key is a path: a JSON pointer such as "/user/id" or a dotted path such as "user.id".
+func isPath(key string) bool {
+	return strings.HasPrefix(key, "/") || strings.Contains(key, ".")
+}
+
+// splitPath splits a key path into its segments. Paths starting with "/" are JSON pointers (RFC 6901),
+// other paths are dotted such as "user.id".
+func splitPath(path string) []string {
+	if strings.HasPrefix(path, "/") {
+		segments := strings.Split(path[1:], "/")
+		for i, segment := range segments {
+			segments[i] = pointerUnescaper.Replace(segment)
+		}
+		return segments
+	}
+	return strings.Split(path, ".")
+}
+
+// lookupPath returns the value at the path segments inside val, walking struct fields by their tag
+// names (see structField), map values by key and list elements by index.
+func (cli *anyClient) lookupPath(val reflect.Value, segments []string) (reflect.Value, bool) {
+	for _, segment := range segments {
+		for (val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface) && !val.IsNil() {
+			val = val.Elem()
+		}
+		switch val.Kind() {
+		case reflect.Struct:
+			field, ok := cli.structField(val, segment, false)
+			if !ok {
+				return reflect.Value{}, false
+			}
+			val = field
+		case reflect.Map:
+			key, ok := cli.pathMapKey(val.Type().Key(), segment)
+			if !ok {
+				return reflect.Value{}, false
+			}
+			if val = val.MapIndex(key); !val.IsValid() {
+				return reflect.Value{}, false
+			}
+		case reflect.Slice, reflect.Array:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= val.Len() {
+				return reflect.Value{}, false
+			}
+			val = val.Index(i)
+		default:
+			return reflect.Value{}, false
+		}
+	}
+	return val, val.IsValid()
+}
+
+// pathMapKey converts a path segment to a key of the map key type with the options of cli.
+func (cli *anyClient) pathMapKey(keyType reflect.Type, segment string) (reflect.Value, bool) {
+	if keyType.Kind() == reflect.String {
+		return reflect.ValueOf(segment).Convert(keyType), true
+	}
+	key := reflect.New(keyType).Elem()
+	if err := newAnyClient(*cli.options).decodeAny(segment, key); err != nil {
+		return reflect.Value{}, false
+	}
+	return key, true
+}
+
+// settablePath returns the settable value at the path segments inside a struct value,
+// nil pointers on the way are allocated.
+func (cli *anyClient) settablePath(val reflect.Value, segments []string) (reflect.Value, bool) {
+	for _, segment := range segments {
+		for val.Kind() == reflect.Ptr {
+			if val.IsNil() {
+				if !val.CanSet() {
+					return reflect.Value{}, false
+				}
+				val.Set(reflect.New(val.Type().Elem()))
+			}
+			val = val.Elem()
+		}
+		if val.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+		field, ok := cli.structField(val, segment, true)
+		if !ok {
+			return reflect.Value{}, false
+		}
+		val = field
+	}
+	return val, val.CanSet()
+}
+
+// assignPaths decodes the values at the input paths of the assignKey option into the output fields or
+// paths they are assigned to, for example "meta.created" to "CreatedAt" or "/user/id" to "owner.id".
+// The output fields that are set are removed from outFieldInfos so that they are not reset.
+func (cli *anyClient) assignPaths(inVal reflect.Value, outVal reflect.Value, outFieldInfos map[string]fieldInfo) error {
+	// the keys are sorted so that paths assigned to the same output are applied in the same order every time
+	inKeys := make([]string, 0, len(cli.options.assignKey))
+	for inKey := range cli.options.assignKey {
+		inKeys = append(inKeys, inKey)
+	}
+	sort.Strings(inKeys)
+	for _, inKey := range inKeys {
+		outKey := cli.options.assignKey[inKey]
+		if !isPath(inKey) && !isPath(outKey) {
+			continue
+		}
+		v, ok := cli.lookupPath(inVal, []string{inKey})
+		if !ok && isPath(inKey) {
+			v, ok = cli.lookupPath(inVal, splitPath(inKey))
+		}
+		if !ok {
+			continue
+		}
+
+		segments := splitPath(outKey)
+		if !isPath(outKey) {
+			segments = []string{outKey}
+		}
+		name := segments[0]
+		field, found := outFieldInfos[name]
+		for k, f := range outFieldInfos {
+			if !found && strings.EqualFold(f.fieldStruct.Name, name) {
+				field, found, name = f, true, k
+			}
+		}
+		target, ok := field.fieldVal, found
+		if !found {
+			target, ok = cli.settablePath(outVal, segments[:1])
+		}
+		if ok && len(segments) > 1 {
+			target, ok = cli.settablePath(target, segments[1:])
+		}
+		if !ok {
+			continue
+		}
+		if err := cli.decodeAny(v.Interface(), target); err != nil {
+			return err
+		}
+		delete(outFieldInfos, name)
+	}
+	return nil
+}
+
+// Get returns the value at a path inside in. The path is dotted with list indexes in brackets,
+// such as "orders[2].items.sku", or a JSON pointer (RFC 6901) such as "/orders/2/items/sku".
+// Struct fields are matched by their tag names as in ToAny, ignored fields are not found and the fields of
+// embedded structs are promoted. Map keys are converted to the key type.
+func Get(in interface{}, path string, op ...Options) (interface{}, error) {
+	cli := newAnyClient(op...)
+	v, ok := cli.lookupPath(reflect.ValueOf(in), pathKeys(path))
+	if !ok || !v.CanInterface() {
+		return nil, errors.Errorf(ErrPathNotFound, path)
+	}
+	return v.Interface(), nil
+}
+
+// GetAs returns the value at a path inside in converted to T, see Get.
+func GetAs[T any](in interface{}, path string, op ...Options) (T, error) {
+	var out T
+	v, err := Get(in, path, op...)
+	if err != nil {
+		return out, err
+	}
+	err = ToAny(v, &out, op...)
+	return out, err
+}
+
+// Set converts v and stores it at a path inside the value out points to, see Get for the path syntax.
+// Nil pointers, maps and interfaces on the way are created, lists grow to the index within the maxLength
+// option, or by at most 1024 elements without it, and the index "-" of a JSON pointer appends to a list.
+func Set(out interface{}, path string, v interface{}, op ...Options) error {
+	outVal := reflect.ValueOf(out)
+	if outVal.Kind() != reflect.Ptr || outVal.IsNil() {
+		return errors.Errorf(ErrUnSupportType, out)
+	}
+	cli := newAnyClient(op...)
+	if err := cli.setPath(outVal.Elem(), parsePath(path), v); err != nil {
+		if _, ok := err.(pathError); ok {
+			return errors.Errorf(ErrPathNotSettable, path)
+		}
+		return err
+	}
+	return nil
+}
+
+// pathError is returned by setPath when a segment can not be walked.
+type pathError struct{}
+
+func (pathError) Error() string { return "path can not be set" }
+
+// String returns the segment as a key, indexes are formatted as numbers.
+func (s flatSegment) String() string {
+	if s.isIndex {
+		return strconv.Itoa(s.index)
+	}
+	return s.key
+}
+
+// parsePath splits a Get or Set path into segments.
+func parsePath(path string) []flatSegment {
+	if path == "" {
+		return nil
+	}
+	if strings.HasPrefix(path, "/") {
+		keys := splitPath(path)
+		segments := make([]flatSegment, len(keys))
+		for i, key := range keys {
+			segments[i] = flatSegment{key: key}
+		}
+		return segments
+	}
+	return splitFlatKey(path, ".")
+}
+
+// pathKeys splits a Get or Set path into the keys of its segments, indexes are formatted as numbers.
+func pathKeys(path string) []string {
+	segments := parsePath(path)
+	keys := make([]string, len(segments))
+	for i, segment := range segments {
+		keys[i] = segment.String()
+	}
+	return keys
+}
+
+// setPath decodes v into the value at the segments inside the settable val.
+func (cli *anyClient) setPath(val reflect.Value, segments []flatSegment, v interface{}) error {
+	if len(segments) == 0 {
+		return cli.decodeAny(v, val)
+	}
+	segment := segments[0]
+	switch val.Kind() {
+	case reflect.Ptr:
+		if val.IsNil() {
+			val.Set(reflect.New(val.Type().Elem()))
+		}
+		return cli.setPath(val.Elem(), segments, v)
+	case reflect.Interface:
+		var child reflect.Value
+		switch {
+		case !val.IsNil():
+			// the value of an interface is not addressable, it is copied and set back
+			child = reflect.New(val.Elem().Type()).Elem()
+			child.Set(val.Elem())
+		case segment.isIndex:
+			child = reflect.ValueOf(&[]interface{}{}).Elem()
+		default:
+			child = reflect.ValueOf(&map[string]interface{}{}).Elem()
+		}
+		if err := cli.setPath(child, segments, v); err != nil {
+			return err
+		}
+		val.Set(child)
+		return nil
+	case reflect.Struct:
+		field, ok := cli.structField(val, segment.String(), true)
+		if !ok || !field.CanSet() {
+			return pathError{}
+		}
+		return cli.setPath(field, segments[1:], v)
+	case reflect.Map:
+		key, ok := cli.pathMapKey(val.Type().Key(), segment.String())
+		if !ok {
+			return pathError{}
+		}
+		if val.IsNil() {
+			val.Set(reflect.MakeMap(val.Type()))
+		}
+		// map values are not addressable, the value is copied and set back
+		child := reflect.New(val.Type().Elem()).Elem()
+		if existing := val.MapIndex(key); existing.IsValid() {
+			child.Set(existing)
+		}
+		if err := cli.setPath(child, segments[1:], v); err != nil {
+			return err
+		}
+		val.SetMapIndex(key, child)
+		return nil
+	case reflect.Slice, reflect.Array:
+		i := segment.index
+		if !segment.isIndex {
+			var err error
+			if segment.key == "-" {
+				i = val.Len()
+			} else if i, err = strconv.Atoi(segment.key); err != nil {
+				return pathError{}
+			}
+		}
+		if i < 0 || i == math.MaxInt || (val.Kind() == reflect.Array && i >= val.Len()) {
+			return pathError{}
+		}
+		if grow := i + 1 - val.Len(); grow > 0 {
+			if cli.options.maxLength <= 0 && grow > maxSetGrowth {
+				return pathError{}
+			}
+			if err := cli.checkLength(i + 1); err != nil {
+				return err
+			}
+			val.Set(reflect.AppendSlice(val, reflect.MakeSlice(val.Type(), grow, grow)))
+		}
+		return cli.setPath(val.Index(i), segments[1:], v)
+	default:
+		return pathError{}
+	}
+}
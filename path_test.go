@@ -0,0 +1,300 @@
+package goany
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{name: "dotted", input: "user.id", expected: []string{"user", "id"}},
+		{name: "pointer", input: "/user/id", expected: []string{"user", "id"}},
+		{name: "pointer escape", input: "/a~1b/c~0d", expected: []string{"a/b", "c~d"}},
+		{name: "single", input: "id", expected: []string{"id"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, splitPath(tt.input))
+		})
+	}
+}
+
+func TestMapKeyFieldPath(t *testing.T) {
+	type user struct {
+		Id int `json:"id"`
+	}
+	type order struct {
+		No   string `json:"no"`
+		User *user  `json:"user"`
+	}
+	orders := []order{{No: "a", User: &user{Id: 1}}, {No: "b", User: &user{Id: 2}}}
+
+	for _, path := range []string{"user.id", "/user/id"} {
+		t.Run("Test with struct "+path, func(t *testing.T) {
+			var out map[int]string
+			err := ToAny(orders, &out, *NewOptions().SetMapKeyField(path).AddHook(func(in interface{}, out reflect.Value) (int, error) {
+				if o, ok := in.(order); ok && out.Kind() == reflect.String {
+					out.SetString(o.No)
+					return DecodeSkip, nil
+				}
+				return DecodeContinue, nil
+			}))
+			assert.NoError(t, err)
+			assert.Equal(t, map[int]string{1: "a", 2: "b"}, out)
+		})
+	}
+
+	t.Run("Test with map elements and index", func(t *testing.T) {
+		in := []map[string]interface{}{
+			{"tags": []string{"x", "y"}, "meta": map[string]interface{}{"id": "7"}},
+			{"tags": []string{"z"}, "meta": map[string]interface{}{"id": "8"}},
+		}
+		var byTag map[string]map[string]interface{}
+		err := ToAny(in, &byTag, *NewOptions().SetMapKeyField("tags.0"))
+		assert.NoError(t, err)
+		assert.Contains(t, byTag, "x")
+		assert.Contains(t, byTag, "z")
+
+		var byID map[int]interface{}
+		err = ToAny(in, &byID, *NewOptions().SetMapKeyField("/meta/id"))
+		assert.NoError(t, err)
+		assert.Contains(t, byID, 7)
+		assert.Contains(t, byID, 8)
+
+		err = ToAny(in, &byID, *NewOptions().SetMapKeyField("meta.missing"))
+		assert.Error(t, err)
+	})
+
+	t.Run("Test with literal dotted key", func(t *testing.T) {
+		in := []map[string]interface{}{{"a.b": 1, "a": map[string]interface{}{"b": 2}}}
+		var out map[int]interface{}
+		err := ToAny(in, &out, *NewOptions().SetMapKeyField("a.b"))
+		assert.NoError(t, err)
+		assert.Contains(t, out, 1)
+	})
+}
+
+func TestAssignKeyPath(t *testing.T) {
+	type audit struct {
+		By string `json:"by"`
+	}
+	type record struct {
+		Name      string    `json:"name"`
+		CreatedAt time.Time `json:"created_at"`
+		Owner     int       `json:"owner"`
+		Audit     *audit    `json:"audit"`
+	}
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("Test with map input", func(t *testing.T) {
+		in := map[string]interface{}{
+			"name": "r",
+			"meta": map[string]interface{}{"created": "2024-01-02 03:04:05", "editor": "ann"},
+			"user": map[string]interface{}{"id": "42"},
+		}
+		op := NewOptions().SetAssignKey(map[string]string{
+			"meta.created": "CreatedAt",
+			"/user/id":     "owner",
+			"meta.editor":  "audit.by",
+		})
+		var out record
+		err := ToAny(in, &out, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, record{Name: "r", CreatedAt: created, Owner: 42, Audit: &audit{By: "ann"}}, out)
+	})
+
+	t.Run("Test with struct input", func(t *testing.T) {
+		type source struct {
+			Name string                 `json:"name"`
+			Meta map[string]interface{} `json:"meta"`
+		}
+		in := source{Name: "s", Meta: map[string]interface{}{"created": created, "editor": "bob"}}
+		op := NewOptions().SetAssignKey(map[string]string{"meta.created": "created_at", "name": "audit.by"})
+		var out record
+		err := ToAny(in, &out, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, record{Name: "s", CreatedAt: created, Audit: &audit{By: "s"}}, out)
+	})
+
+	t.Run("Test with missing path", func(t *testing.T) {
+		op := NewOptions().SetAssignKey(map[string]string{"meta.created": "created_at", "x": "nothing.here"})
+		var out record
+		err := ToAny(map[string]interface{}{"name": "n", "x": 1}, &out, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, record{Name: "n"}, out)
+	})
+
+	t.Run("Test with paths to the same field", func(t *testing.T) {
+		in := map[string]interface{}{"a": map[string]interface{}{"id": 1}, "b": map[string]interface{}{"id": 2}}
+		op := NewOptions().SetAssignKey(map[string]string{"/b/id": "owner", "/a/id": "owner"})
+		for i := 0; i < 10; i++ {
+			var out record
+			assert.NoError(t, ToAny(in, &out, *op))
+			assert.Equal(t, 2, out.Owner)
+		}
+	})
+
+	t.Run("Test with map key options", func(t *testing.T) {
+		op := NewOptions().SetNumberFormat(NumberRadix)
+		v, err := Get(map[int]string{31: "x"}, "0x1F", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, "x", v)
+
+		_, err = Get(map[int]string{31: "x"}, "0x1F")
+		assert.Error(t, err)
+	})
+}
+
+func TestGetSet(t *testing.T) {
+	type item struct {
+		Sku string `json:"sku"`
+		Qty int    `json:"qty"`
+	}
+	type order struct {
+		Id    int               `json:"id"`
+		Items []item            `json:"items"`
+		Meta  map[string]string `json:"meta"`
+		Ref   *item             `json:"ref"`
+	}
+	type shop struct {
+		Orders []order `json:"orders"`
+	}
+	in := shop{Orders: []order{
+		{Id: 1, Items: []item{{Sku: "a", Qty: 1}}},
+		{Id: 2, Items: []item{{Sku: "b", Qty: 2}, {Sku: "c", Qty: 3}}, Meta: map[string]string{"note": "7"}},
+	}}
+
+	t.Run("Test with get", func(t *testing.T) {
+		tests := []struct {
+			path     string
+			expected interface{}
+		}{
+			{path: "orders[1].items[1].sku", expected: "c"},
+			{path: "/orders/1/items/0/qty", expected: 2},
+			{path: "orders[1].meta.note", expected: "7"},
+		}
+		for _, tt := range tests {
+			v, err := Get(in, tt.path)
+			assert.NoError(t, err, tt.path)
+			assert.Equal(t, tt.expected, v, tt.path)
+		}
+
+		v, err := Get(&in, "")
+		assert.NoError(t, err)
+		assert.Equal(t, &in, v)
+
+		for _, path := range []string{"orders[5].id", "orders[0].missing", "orders[0].ref.sku", "Orders[0].Id"} {
+			_, err = Get(in, path)
+			assert.Equal(t, errors.Errorf(ErrPathNotFound, path).Error(), err.Error(), path)
+		}
+	})
+
+	t.Run("Test with embedded and ignored fields", func(t *testing.T) {
+		config := diffConfig{DiffBase: DiffBase{Env: "dev"}, Name: "app", Skip: "x"}
+		v, err := Get(config, "env")
+		assert.NoError(t, err)
+		assert.Equal(t, "dev", v)
+
+		for _, path := range []string{"Skip", "skip", "-", "secret"} {
+			_, err = Get(config, path)
+			assert.Equal(t, errors.Errorf(ErrPathNotFound, path).Error(), err.Error(), path)
+		}
+
+		assert.NoError(t, Set(&config, "env", "prod"))
+		assert.Equal(t, "prod", config.Env)
+		assert.Error(t, Set(&config, "Skip", "y"))
+		assert.Equal(t, "x", config.Skip)
+
+		type node struct {
+			*DiffBase
+			Name string `json:"name"`
+		}
+		var n node
+		assert.Error(t, Set(&n, "missing", 1))
+		assert.Nil(t, n.DiffBase)
+		assert.NoError(t, Set(&n, "env", "dev"))
+		assert.Equal(t, &DiffBase{Env: "dev"}, n.DiffBase)
+	})
+
+	t.Run("Test with get as", func(t *testing.T) {
+		n, err := GetAs[int](in, "orders[1].meta.note")
+		assert.NoError(t, err)
+		assert.Equal(t, 7, n)
+
+		s, err := GetAs[string](map[string]interface{}{"a": []interface{}{map[string]interface{}{"b": 1.5}}}, "/a/0/b")
+		assert.NoError(t, err)
+		assert.Equal(t, "1.5", s)
+
+		_, err = GetAs[int](in, "orders[0].items[0].sku")
+		assert.Error(t, err)
+	})
+
+	t.Run("Test with set struct", func(t *testing.T) {
+		var out shop
+		assert.NoError(t, Set(&out, "orders[1].items[0].qty", "5"))
+		assert.NoError(t, Set(&out, "orders[1].meta.note", 8))
+		assert.NoError(t, Set(&out, "/orders/1/ref/sku", "r"))
+		assert.NoError(t, Set(&out, "/orders/1/items/-", map[string]interface{}{"sku": "z"}))
+		assert.Equal(t, shop{Orders: []order{{}, {
+			Items: []item{{Qty: 5}, {Sku: "z"}},
+			Meta:  map[string]string{"note": "8"},
+			Ref:   &item{Sku: "r"},
+		}}}, out)
+
+		assert.Equal(t, errors.Errorf(ErrPathNotSettable, "orders[0].missing").Error(), Set(&out, "orders[0].missing", 1).Error())
+		assert.Error(t, Set(&out, "orders[0].id", "x"))
+		assert.Error(t, Set(out, "orders[0].id", 1))
+	})
+
+	t.Run("Test with set map", func(t *testing.T) {
+		out := map[string]interface{}{"a": map[string]interface{}{"keep": true}}
+		assert.NoError(t, Set(&out, "a.b[2].c", 1))
+		assert.NoError(t, Set(&out, "/x/y", "z"))
+		assert.Equal(t, map[string]interface{}{
+			"a": map[string]interface{}{
+				"keep": true,
+				"b":    []interface{}{nil, nil, map[string]interface{}{"c": 1}},
+			},
+			"x": map[string]interface{}{"y": "z"},
+		}, out)
+
+		counts := map[int][]int{}
+		assert.NoError(t, Set(&counts, "3[1]", "4"))
+		assert.Equal(t, map[int][]int{3: {0, 4}}, counts)
+
+		var arr [2]string
+		assert.NoError(t, Set(&arr, "[1]", 9))
+		assert.Equal(t, [2]string{"", "9"}, arr)
+		assert.Error(t, Set(&arr, "[2]", 9))
+	})
+
+	t.Run("Test with set beyond max length", func(t *testing.T) {
+		var list []int
+		err := Set(&list, "[50000000]", 1, *NewOptions().SetMaxLength(100))
+		var limitErr *LimitError
+		assert.True(t, errors.As(err, &limitErr))
+		assert.Equal(t, &LimitError{Kind: LimitLength, Limit: 100, Size: 50000001}, limitErr)
+		assert.Empty(t, list)
+
+		assert.NoError(t, Set(&list, "[99]", 1, *NewOptions().SetMaxLength(100)))
+		assert.Len(t, list, 100)
+		assert.Equal(t, 1, list[99])
+
+		var m map[string][]int
+		for _, path := range []string{"a[4611686018427387904]", "a[9223372036854775807]", "a[1024]"} {
+			assert.Equal(t, errors.Errorf(ErrPathNotSettable, path).Error(), Set(&m, path, 1).Error(), path)
+		}
+		err = Set(&m, "a[9223372036854775807]", 1, *NewOptions().SetMaxLength(100))
+		assert.Equal(t, errors.Errorf(ErrPathNotSettable, "a[9223372036854775807]").Error(), err.Error())
+		assert.NoError(t, Set(&m, "a[1023]", 1))
+		assert.Len(t, m["a"], 1024)
+	})
+}
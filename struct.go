@@ -14,6 +14,14 @@ func (cli *anyClient) decodeStruct(in interface{}, outVal reflect.Value) error {
 
 	switch inVal.Kind() {
 	case reflect.Map:
+		// Flat keys such as "db.host" are expanded into nested maps first.
+		if cli.options.flatKeys && hasFlatKey(inVal, cli.options.flatSep) {
+			nested, err := Unflatten(inVal.Interface(), cli.options.flatSep)
+			if err != nil {
+				return err
+			}
+			return cli.mapToStruct(nested, outVal)
+		}
 		return cli.mapToStruct(in, outVal)
 	case reflect.Struct:
 		return cli.structToStruct(in, outVal)
@@ -30,6 +38,7 @@ func (cli *anyClient) decodeStruct(in interface{}, outVal reflect.Value) error {
 
 // mapToStruct decodes a map input into a struct output value. It iterates over each field
 // in the input map and attempts to match and set the corresponding field in the output struct.
+// The paths of the assignKey option are assigned afterwards, see assignPaths.
 func (cli *anyClient) mapToStruct(in interface{}, outVal reflect.Value) error {
 	// Create a new instance of the output value's type.
 	basicOutVal := reflect.New(outVal.Type())
@@ -49,7 +58,11 @@ func (cli *anyClient) mapToStruct(in interface{}, outVal reflect.Value) error {
 				continue
 			}
 
-			if err := cli.decodeAny(inFieldInfo.fieldVal.Interface(), outFieldInfo.fieldVal); err != nil {
+			fieldCli, err := cli.fieldClient(outFieldInfo.fieldStruct)
+			if err != nil {
+				return err
+			}
+			if err := fieldCli.decodeAt(flatSegment{key: outFieldInfo.fieldName}, inFieldInfo.fieldVal.Interface(), outFieldInfo.fieldVal); err != nil {
 				return err
 			}
 			// Remove the field from the map of output fields to avoid multiple assignments.
@@ -60,6 +73,9 @@ func (cli *anyClient) mapToStruct(in interface{}, outVal reflect.Value) error {
 			}
 		}
 	}
+	if err := cli.assignPaths(inVal, basicOutValElem, outFieldInfos); err != nil {
+		return err
+	}
 	//if outfield not match, set it to nil
 	for _, v := range outFieldInfos {
 		if v.fieldStruct.Anonymous {
@@ -93,7 +109,11 @@ func (cli *anyClient) structToStruct(in interface{}, outVal reflect.Value) error
 		// If no matching field is found, skip to the next field.
 		matchOuts := matchOutField(inFieldInfo.fieldName, outFieldInfos, cli.options.assignKey)
 		for _, outFieldInfo := range matchOuts {
-			if err := cli.decodeAny(inFieldInfo.fieldVal.Interface(), outFieldInfo.fieldVal); err != nil {
+			fieldCli, err := cli.fieldClient(outFieldInfo.fieldStruct)
+			if err != nil {
+				return err
+			}
+			if err := fieldCli.decodeAt(flatSegment{key: outFieldInfo.fieldName}, inFieldInfo.fieldVal.Interface(), outFieldInfo.fieldVal); err != nil {
 				return err
 			}
 
@@ -111,6 +131,9 @@ func (cli *anyClient) structToStruct(in interface{}, outVal reflect.Value) error
 			currentAnonymous = inFieldInfo.fieldName
 		}
 	}
+	if err := cli.assignPaths(reflect.ValueOf(in), basicOutVal, outFieldInfos); err != nil {
+		return err
+	}
 	//if outfield not match, set it to nil
 	for _, v := range outFieldInfos {
 		if v.fieldStruct.Anonymous { //anonymous field can not be set to nil
@@ -311,6 +311,12 @@ func TestDecodeStruct_String(t *testing.T) {
 		B string `json:"b"`
 		C abc    `json:"c"`
 	}
+	type ids struct {
+		Id  int64   `json:"id"`
+		Uid uint64  `json:"uid"`
+		F   float64 `json:"f"`
+		S   string  `json:"s"`
+	}
 
 	tests := []structTest{
 		{
@@ -331,6 +337,18 @@ func TestDecodeStruct_String(t *testing.T) {
 			output:   make([]*abc, 0),
 			expected: []*abc{{A: "1"}, {B: "2"}},
 		},
+		{
+			name:     "Test with string large id, keep precision",
+			input:    `{"id": 9007199254740993, "uid": 18446744073709551615, "f": 1.5, "s": 9223372036854775807}`,
+			output:   new(ids),
+			expected: &ids{Id: 9007199254740993, Uid: 18446744073709551615, F: 1.5, S: "9223372036854775807"},
+		},
+		{
+			name:     "Test with string float to int",
+			input:    `{"id": 12.0, "uid": 1e3}`,
+			output:   new(ids),
+			expected: &ids{Id: 12, Uid: 1000},
+		},
 	}
 
 	for _, tt := range tests {
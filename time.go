@@ -1,8 +1,12 @@
 package goany
 
 import (
+	"encoding/json"
 	"github.com/pkg/errors"
+	"math"
 	"reflect"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -29,29 +33,26 @@ var (
 		"15:04:05",
 		"2006/01/02 15:04:05",
 		"2006-01-02T15:04:05",
+		"2006-01-02 15:04",
 	}
 )
 
 // ToTime attempts to convert an interface value to a time.Time value
 func ToTime(v interface{}, op ...Options) time.Time {
-	if len(op) == 0 {
-		op = append(op, *NewOptions())
-	}
-	t, _ := toTimeLocationE(v, op[0].location)
+	t, _ := ToTimeE(v, op...)
 	return t
 }
 
 // ToTimeE attempts to convert an interface value to a time.Time value.
 func ToTimeE(v interface{}, op ...Options) (time.Time, error) {
-	if len(op) == 0 {
-		op = append(op, *NewOptions())
-	}
-	return toTimeLocationE(v, op[0].location)
+	var out time.Time
+	err := toScalarE(v, &out, op)
+	return out, err
 }
 
 // decodeTime decodes an input value into a time.Time output value
 func (cli *anyClient) decodeTime(in interface{}, outVal reflect.Value) error {
-	t, err := toTimeLocationE(in, cli.options.location)
+	t, err := toTimeE(in, *cli.options)
 	if err != nil {
 		return err
 	}
@@ -59,44 +60,275 @@ func (cli *anyClient) decodeTime(in interface{}, outVal reflect.Value) error {
 	return nil
 }
 
-// toTimeLocationE converts an interface value to time.Time considering the provided location.
-// It handles struct types (specifically time.Time), strings in various date-time formats, and
-// numeric types representing Unix time. The function returns the converted time.Time value and
-// an error if the conversion fails. If the location is nil, UTC is used as the default.
-func toTimeLocationE(in interface{}, location *time.Location) (time.Time, error) {
+// toTimeE converts an interface value to time.Time considering the location of op.
+// It handles struct types (time.Time, named time types, Date and TimeOfDay), strings in various date-time formats, and
+// numbers representing Unix time in the time unit of op, floats may carry a fraction of the unit.
+// The function returns the converted time.Time value and an error if the conversion fails.
+// If the location is nil, UTC is used as the default, with time normalize the result is converted to it.
+func toTimeE(in interface{}, op Options) (time.Time, error) {
+	location := op.location
 	if location == nil {
 		location = time.UTC
 	}
+	t, err := toTimeInE(in, location, op)
+	if err != nil || !op.timeNormalize || t.IsZero() {
+		return t, err
+	}
+	return t.In(location), nil
+}
+
+// toTimeInE converts an interface value to time.Time, strings without an offset are parsed in location.
+func toTimeInE(in interface{}, location *time.Location, op Options) (time.Time, error) {
 	in = Indirect(in)
 	if CheckInIsNil(in) {
 		return time.Time{}, nil
 	}
+	if n, ok := in.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			return unixToTime(i, op.timeUnit).In(location), nil
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return time.Time{}, errors.Errorf(ErrUnableConvertTime, in)
+		}
+		return floatToTime(f, in, op.timeUnit, location)
+	}
 	switch reflect.TypeOf(in).Kind() {
 	case reflect.Struct:
-		switch in.(type) {
-		case time.Time:
-			return in.(time.Time), nil
-		default:
-			return time.Time{}, errors.Errorf(ErrUnableConvertTime, in)
+		if t, ok := asTime(in, location); ok {
+			return t, nil
 		}
+		return time.Time{}, errors.Errorf(ErrUnableConvertTime, in)
 	case reflect.String:
-		return stringToTime(in.(string), location)
-	case reflect.Int, reflect.Int32, reflect.Int64:
-		return time.Unix(reflect.ValueOf(in).Int(), 0).In(location), nil
-	case reflect.Uint, reflect.Uint32, reflect.Uint64:
-		return time.Unix(int64(reflect.ValueOf(in).Uint()), 0).In(location), nil
+		return stringToTime(reflect.ValueOf(in).String(), location, op)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return unixToTime(reflect.ValueOf(in).Int(), op.timeUnit).In(location), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := reflect.ValueOf(in).Uint()
+		if u > math.MaxInt64 {
+			return time.Time{}, errors.Errorf(ErrUnableConvertTime, in)
+		}
+		return unixToTime(int64(u), op.timeUnit).In(location), nil
+	case reflect.Float32, reflect.Float64:
+		return floatToTime(reflect.ValueOf(in).Float(), in, op.timeUnit, location)
 	default:
 		return time.Time{}, errors.Errorf(ErrUnableConvertTime, in)
 	}
 }
 
-// Compatible with time conversion in many formats
-func stringToTime(v string, location *time.Location) (time.Time, error) {
-	for _, value := range timeType {
+// Compatible with time conversion in many formats, the layouts are tried in the order
+// given by parseLayouts and the error lists every layout that was attempted.
+// ISO-8601 week, ordinal and basic dates are parsed after the layouts, see parseISOTime.
+// If relative time is enabled, expressions like "now" or "3 days ago" are parsed last.
+// In strict mode only the time format is accepted, as a layout or as the ISO-8601 form it names.
+func stringToTime(in string, location *time.Location, op Options) (time.Time, error) {
+	v := in
+	if op.timeZoneNames {
+		v, location = cutZoneName(v, location)
+	}
+	layouts := op.parseLayouts()
+	for _, value := range layouts {
 		t, err := time.ParseInLocation(value, v, location)
 		if err == nil {
 			return t, nil
 		}
 	}
-	return time.Time{}, errors.Errorf(ErrUnableConvertTime, v)
+	// strict mode only accepts the ISO-8601 form named by the time format
+	if t, form, ok := parseISOTime(v, location); ok && (!op.timeStrict || form == op.timeFormat) {
+		return t, nil
+	}
+	if op.relativeTime && !op.timeStrict {
+		if t, ok := parseRelativeTime(v, location, op); ok {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.Errorf(ErrUnableParseTime, in, layouts)
+}
+
+// cutZoneName removes an IANA zone name from the end of a time string and returns it as the location.
+// The string and location are returned unchanged if the last word is not a known zone.
+func cutZoneName(v string, location *time.Location) (string, *time.Location) {
+	v = strings.TrimSpace(v)
+	i := strings.LastIndexByte(v, ' ')
+	if i <= 0 {
+		return v, location
+	}
+	name := v[i+1:]
+	if name != "UTC" && !strings.Contains(name, "/") {
+		return v, location
+	}
+	zone, err := loadLocation(name)
+	if err != nil {
+		return v, location
+	}
+	return strings.TrimSpace(v[:i]), zone
+}
+
+// locations caches the locations loaded by name, time.LoadLocation reads the zone database every time.
+var locations sync.Map
+
+// loadLocation returns the location with the given IANA name.
+func loadLocation(name string) (*time.Location, error) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errors.Errorf(ErrUnknownTimeZone, name)
+	}
+	locations.Store(name, loc)
+	return loc, nil
+}
+
+// fieldClient returns the client that decodes a struct field. A field with a time zone tag option
+// gets a copy of cli whose location is that zone, and whose output times are normalized to it.
+// A field with a time format tag option parses its strings with that format first and is exported
+// as a string in that format, unless the options export times otherwise.
+func (cli *anyClient) fieldClient(field reflect.StructField) (*anyClient, error) {
+	zone, hasZone := tagOption(field, TagTimeZone)
+	format, hasFormat := tagOption(field, TagTimeFormat)
+	if !hasZone && !hasFormat {
+		return cli, nil
+	}
+	op := *cli.options
+	if hasZone {
+		loc, err := loadLocation(zone)
+		if err != nil {
+			return nil, err
+		}
+		op.location = loc
+		op.timeNormalize = true
+	}
+	if hasFormat {
+		op.timeFormat = format
+		if !op.timeStrict && format != TimeFormatUnix && format != TimeFormatISOWeek && format != TimeFormatISOOrdinal {
+			op.PrependTimeLayouts(format)
+		}
+		if op.timeExport == TimeExportTime {
+			op.timeExport = TimeExportString
+		}
+	}
+	return &anyClient{options: &op, state: cli.state}, nil
+}
+
+// exportTime returns a time as it is stored when exported to an interface, in the time export mode of op.
+// It returns false if in is not a time.Time or named time type, or if times are kept as they are.
+func exportTime(in interface{}, op Options) (interface{}, bool) {
+	if op.timeExport == TimeExportTime {
+		return nil, false
+	}
+	switch in.(type) {
+	case Date, TimeOfDay:
+		return nil, false
+	}
+	t, ok := asTime(in, op.location)
+	if !ok {
+		return nil, false
+	}
+	if op.timeNormalize && op.location != nil {
+		t = t.In(op.location)
+	}
+	switch op.timeExport {
+	case TimeExportUnix:
+		return t.Unix(), true
+	case TimeExportUnixMilli:
+		return t.UnixMilli(), true
+	default:
+		s, err := toStringE(t, op)
+		return s, err == nil
+	}
+}
+
+// parseLayouts returns the layouts used to parse time strings. In strict mode only the
+// time format is accepted, otherwise the layouts of the options or the default timeType.
+func (op *Options) parseLayouts() []string {
+	if op.timeStrict {
+		return []string{op.timeFormat}
+	}
+	if op.timeLayouts != nil {
+		return op.timeLayouts
+	}
+	return timeType
+}
+
+// unixToTime converts a Unix timestamp in the given unit to a time.Time.
+func unixToTime(v int64, unit int) time.Time {
+	switch detectTimeUnit(float64(v), unit) {
+	case TimeUnitMilli:
+		return time.UnixMilli(v)
+	case TimeUnitMicro:
+		return time.UnixMicro(v)
+	case TimeUnitNano:
+		return time.Unix(0, v)
+	default:
+		return time.Unix(v, 0)
+	}
+}
+
+// floatToTime converts a Unix timestamp with a fraction in the given unit to a time.Time,
+// the fraction is rounded to the nearest nanosecond.
+func floatToTime(f float64, in interface{}, unit int, location *time.Location) (time.Time, error) {
+	unitNanos := timeUnitNanos(detectTimeUnit(f, unit))
+	whole := math.Floor(f)
+	if math.IsNaN(f) || whole >= math.MaxInt64 || whole < math.MinInt64 {
+		return time.Time{}, errors.Errorf(ErrUnableConvertTime, in)
+	}
+	sec, rem := int64(whole)/int64(time.Second/unitNanos), int64(whole)%int64(time.Second/unitNanos)
+	nsec := rem*int64(unitNanos) + int64(math.Round((f-whole)*float64(unitNanos)))
+	return time.Unix(sec, nsec).In(location), nil
+}
+
+// timeToUnix returns the Unix timestamp of t in the given unit, TimeUnitAuto uses seconds.
+func timeToUnix(t time.Time, unit int) int64 {
+	switch unit {
+	case TimeUnitMilli:
+		return t.UnixMilli()
+	case TimeUnitMicro:
+		return t.UnixMicro()
+	case TimeUnitNano:
+		return t.UnixNano()
+	default:
+		return t.Unix()
+	}
+}
+
+// timeToUnixFloat returns the Unix timestamp of t in the given unit with the fraction of the unit.
+func timeToUnixFloat(t time.Time, unit int) float64 {
+	if unit == TimeUnitAuto {
+		unit = TimeUnitSecond
+	}
+	unitNanos := int64(timeUnitNanos(unit))
+	return float64(timeToUnix(t, unit)) + float64(int64(t.Nanosecond())%unitNanos)/float64(unitNanos)
+}
+
+// detectTimeUnit returns unit, or for TimeUnitAuto the unit guessed from the magnitude of v:
+// below 1e11 is seconds (until year 5138), then milliseconds, microseconds and nanoseconds.
+func detectTimeUnit(v float64, unit int) int {
+	if unit != TimeUnitAuto {
+		return unit
+	}
+	switch v = math.Abs(v); {
+	case v < 1e11:
+		return TimeUnitSecond
+	case v < 1e14:
+		return TimeUnitMilli
+	case v < 1e17:
+		return TimeUnitMicro
+	default:
+		return TimeUnitNano
+	}
+}
+
+// timeUnitNanos returns the length of a time unit.
+func timeUnitNanos(unit int) time.Duration {
+	switch unit {
+	case TimeUnitMilli:
+		return time.Millisecond
+	case TimeUnitMicro:
+		return time.Microsecond
+	case TimeUnitNano:
+		return time.Nanosecond
+	default:
+		return time.Second
+	}
 }
@@ -0,0 +1,108 @@
+package goany
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseRelativeTime parses relative time expressions evaluated against the clock of op:
+//   - keywords: "now", "today", "yesterday", "tomorrow", days start at midnight in location
+//   - signed durations: "-15m", "+1d12h", see parseDuration
+//   - phrases: "in 2 hours", "3 days ago", "an hour ago"
+//   - a base and a signed duration: "2024-01-01+7d", "today-1h"
+//
+// It returns false if the string is not a relative time expression.
+func parseRelativeTime(v string, location *time.Location, op Options) (time.Time, bool) {
+	s := strings.ToLower(strings.TrimSpace(v))
+	now := op.now().In(location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+
+	switch s {
+	case "now":
+		return now, true
+	case "today":
+		return today, true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true
+	}
+
+	if strings.HasPrefix(s, "+") || strings.HasPrefix(s, "-") {
+		if d, err := parseSignedDuration(s); err == nil {
+			return now.Add(d), true
+		}
+	}
+
+	if fields := strings.Fields(s); len(fields) == 3 {
+		switch {
+		case fields[0] == "in":
+			return addRelative(now, fields[1], fields[2], 1)
+		case fields[2] == "ago":
+			return addRelative(now, fields[0], fields[1], -1)
+		}
+	}
+
+	// a base time followed by a signed duration, the sign is searched from the end
+	// so that the dashes of a date stay in the base.
+	for i := len(v) - 1; i > 0; i-- {
+		if v[i] != '+' && v[i] != '-' {
+			continue
+		}
+		d, err := parseSignedDuration(v[i:])
+		if err != nil {
+			continue
+		}
+		if base, err := stringToTime(v[:i], location, op); err == nil {
+			return base.Add(d), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseSignedDuration parses a duration that starts with "+" or "-", numbers without a unit are rejected.
+func parseSignedDuration(s string) (time.Duration, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	return parseExtendedDuration(s)
+}
+
+// addRelative adds amount units to t, multiplied by sign. The amount is an integer or "a"/"an",
+// days, weeks, months and years follow the calendar, smaller units are fixed durations.
+func addRelative(t time.Time, amount, unit string, sign int) (time.Time, bool) {
+	n := 1
+	if amount != "a" && amount != "an" {
+		var err error
+		if n, err = strconv.Atoi(amount); err != nil {
+			return time.Time{}, false
+		}
+	}
+	n *= sign
+	switch strings.TrimSuffix(unit, "s") {
+	case "sec", "second":
+		return t.Add(time.Duration(n) * time.Second), true
+	case "min", "minute":
+		return t.Add(time.Duration(n) * time.Minute), true
+	case "hr", "hour":
+		return t.Add(time.Duration(n) * time.Hour), true
+	case "day":
+		return t.AddDate(0, 0, n), true
+	case "week":
+		return t.AddDate(0, 0, 7*n), true
+	case "month":
+		return t.AddDate(0, n, 0), true
+	case "year":
+		return t.AddDate(n, 0, 0), true
+	}
+	return time.Time{}, false
+}
+
+// now returns the current time from the clock of the options, time.Now if none is set.
+func (op *Options) now() time.Time {
+	if op.clock != nil {
+		return op.clock()
+	}
+	return time.Now()
+}
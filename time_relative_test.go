@@ -0,0 +1,80 @@
+package goany
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelativeTime(t *testing.T) {
+	locationShanghai, _ := time.LoadLocation("Asia/Shanghai")
+	now := time.Date(2024, 3, 10, 15, 30, 0, 0, time.UTC)
+	op := NewOptions().SetRelativeTime(true).SetClock(func() time.Time { return now })
+
+	tests := []struct {
+		name     string
+		input    string
+		op       *Options
+		expected time.Time
+	}{
+		{name: "now", input: "now", expected: now},
+		{name: "today", input: " Today ", expected: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{name: "yesterday", input: "yesterday", expected: time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)},
+		{name: "tomorrow", input: "tomorrow", expected: time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)},
+		{name: "minus duration", input: "-15m", expected: now.Add(-15 * time.Minute)},
+		{name: "plus day", input: "+1d12h", expected: now.Add(36 * time.Hour)},
+		{name: "in hours", input: "in 2 hours", expected: now.Add(2 * time.Hour)},
+		{name: "days ago", input: "3 days ago", expected: now.AddDate(0, 0, -3)},
+		{name: "an hour ago", input: "an hour ago", expected: now.Add(-time.Hour)},
+		{name: "in a month", input: "in 1 month", expected: now.AddDate(0, 1, 0)},
+		{name: "date plus", input: "2024-01-01+7d", expected: time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)},
+		{name: "date minus", input: "2024-01-01-1h", expected: time.Date(2023, 12, 31, 23, 0, 0, 0, time.UTC)},
+		{name: "keyword plus", input: "today+8h", expected: time.Date(2024, 3, 10, 8, 0, 0, 0, time.UTC)},
+		{
+			name:     "today in location",
+			input:    "today",
+			op:       NewOptions().SetRelativeTime(true).SetClock(func() time.Time { return now }).SetLocation(locationShanghai),
+			expected: time.Date(2024, 3, 10, 0, 0, 0, 0, locationShanghai),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.op == nil {
+				tt.op = op
+			}
+			out, err := ToTimeE(tt.input, *tt.op)
+			assert.NoError(t, err)
+			assert.True(t, tt.expected.Equal(out), "expected %v, got %v", tt.expected, out)
+		})
+	}
+
+	t.Run("Test with absolute time still parsed", func(t *testing.T) {
+		out, err := ToTimeE("2020-01-01T21:30:03+07:00", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1577889003), out.Unix())
+	})
+
+	t.Run("Test with invalid", func(t *testing.T) {
+		for _, in := range []string{"later", "in two hours", "3 fortnights ago", "-15", "2024-01-01+x"} {
+			_, err := ToTimeE(in, *op)
+			assert.Error(t, err, in)
+		}
+	})
+
+	t.Run("Test with disabled", func(t *testing.T) {
+		_, err := ToTimeE("now")
+		assert.Error(t, err)
+	})
+
+	t.Run("Test with struct field", func(t *testing.T) {
+		type filter struct {
+			From time.Time `json:"from"`
+			To   time.Time `json:"to"`
+		}
+		var out filter
+		err := ToAny(map[string]interface{}{"from": "7 days ago", "to": "now"}, &out, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, filter{From: now.AddDate(0, 0, -7), To: now}, out)
+	})
+}
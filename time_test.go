@@ -1,6 +1,7 @@
 package goany
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/pkg/errors"
 	"testing"
@@ -59,7 +60,7 @@ func TestToTimeE(t *testing.T) {
 			name:     "invalid string input",
 			input:    "invalid",
 			expected: time.Time{},
-			err:      errors.Errorf(ErrUnableConvertTime, "invalid"),
+			err:      errors.Errorf(ErrUnableParseTime, "invalid", timeType),
 		},
 		{
 			name:     "integer input",
@@ -173,3 +174,191 @@ func TestTimeToString(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expected, out)
 }
+
+func TestTimeUnit(t *testing.T) {
+	expected := time.Date(2024, 1, 2, 3, 4, 5, 678000000, time.UTC)
+
+	t.Run("Test with unit input", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			input interface{}
+			unit  int
+		}{
+			{name: "milli", input: int64(1704164645678), unit: TimeUnitMilli},
+			{name: "micro", input: uint64(1704164645678000), unit: TimeUnitMicro},
+			{name: "nano", input: int64(1704164645678000000), unit: TimeUnitNano},
+			{name: "float second", input: 1704164645.678, unit: TimeUnitSecond},
+			{name: "float milli", input: json.Number("1704164645678.0"), unit: TimeUnitMilli},
+			{name: "auto milli", input: json.Number("1704164645678"), unit: TimeUnitAuto},
+			{name: "auto micro", input: int64(1704164645678000), unit: TimeUnitAuto},
+			{name: "auto nano", input: int64(1704164645678000000), unit: TimeUnitAuto},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				out, err := ToTimeE(tt.input, *NewOptions().SetTimeUnit(tt.unit))
+				assert.NoError(t, err)
+				assert.WithinDuration(t, expected, out, time.Microsecond) // float seconds are not exact
+			})
+		}
+
+		out, err := ToTimeE(1704164645, *NewOptions().SetTimeUnit(TimeUnitAuto))
+		assert.NoError(t, err)
+		assert.Equal(t, expected.Truncate(time.Second), out)
+	})
+
+	t.Run("Test with unit output", func(t *testing.T) {
+		op := NewOptions().SetTimeUnit(TimeUnitMilli)
+		v, err := ToInt64E(expected, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1704164645678), v)
+
+		f, err := ToFloat64E(expected)
+		assert.NoError(t, err)
+		assert.InDelta(t, 1704164645.678, f, 1e-6)
+
+		s, err := ToStringE(expected, *op.SetTimeFormat(TimeFormatUnix))
+		assert.NoError(t, err)
+		assert.Equal(t, "1704164645678", s)
+	})
+
+	t.Run("Test with round trip", func(t *testing.T) {
+		type event struct {
+			At time.Time `json:"at"`
+		}
+		op := NewOptions().SetTimeUnit(TimeUnitMilli).SetTimeFormat(TimeFormatUnix)
+		var e event
+		err := ToAny(`{"at": 1704164645678}`, &e, *op)
+		assert.NoError(t, err)
+		assert.True(t, expected.Equal(e.At))
+
+		var m map[string]string
+		err = ToAny(e, &m, *op)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"at": "1704164645678"}, m)
+	})
+}
+
+func TestTimeLayouts(t *testing.T) {
+	t.Run("Test with prepend layouts", func(t *testing.T) {
+		op := NewOptions().PrependTimeLayouts("02/01/2006")
+		out, err := ToTimeE("03/02/2024", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC), out)
+
+		out, err = ToTimeE("2024-02-03", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC), out)
+
+		out, err = ToTimeE("03/02/2024", *NewOptions().PrependTimeLayouts("01/02/2006"))
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), out)
+	})
+
+	t.Run("Test with replace layouts", func(t *testing.T) {
+		op := NewOptions().SetTimeLayouts("2006.01.02")
+		out, err := ToTimeE("2024.02.03", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC), out)
+
+		_, err = ToTimeE("2024-02-03", *op)
+		assert.EqualError(t, err, `unable to convert "2024-02-03"(type string) to time, tried layouts ["2006.01.02"]`)
+	})
+
+	t.Run("Test with strict", func(t *testing.T) {
+		op := NewOptions().SetTimeStrict(true)
+		out, err := ToTimeE("2024-02-03 04:05:06", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC), out)
+
+		_, err = ToTimeE("2024-02-03", *op)
+		assert.Error(t, err)
+
+		out, err = ToTimeE("2024-02-03", *op.SetTimeFormat("2006-01-02"))
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC), out)
+	})
+
+	t.Run("Test options are not shared", func(t *testing.T) {
+		NewOptions().PrependTimeLayouts("02/01/2006")
+		_, err := ToTimeE("03/02/2024")
+		assert.Error(t, err)
+	})
+}
+
+func TestTimeZone(t *testing.T) {
+	shanghai, _ := time.LoadLocation("Asia/Shanghai")
+	newYork, _ := time.LoadLocation("America/New_York")
+
+	t.Run("Test with normalize", func(t *testing.T) {
+		in := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+		v, err := ToTimeE(in, *NewOptions().SetLocation(shanghai))
+		assert.NoError(t, err)
+		assert.Equal(t, time.UTC, v.Location())
+
+		v, err = ToTimeE(in, *NewOptions().SetLocation(shanghai).SetTimeNormalize(true))
+		assert.NoError(t, err)
+		assert.Equal(t, shanghai, v.Location())
+		assert.True(t, in.Equal(v))
+
+		v, err = ToTimeE("2024-01-01T10:00:00+02:00", *NewOptions().SetLocation(shanghai).SetTimeNormalize(true))
+		assert.NoError(t, err)
+		assert.Equal(t, "2024-01-01 16:00:00", v.Format("2006-01-02 15:04:05"))
+	})
+
+	t.Run("Test with zone names", func(t *testing.T) {
+		op := NewOptions().SetTimeZoneNames(true)
+		v, err := ToTimeE("2024-01-01 10:00:00 Asia/Shanghai", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 1, 1, 10, 0, 0, 0, shanghai), v)
+
+		v, err = ToTimeE("2024-07-01 America/New_York", *op)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 7, 1, 0, 0, 0, 0, newYork), v)
+
+		v, err = ToTimeE("2024-01-01 10:00:00 Asia/Shanghai", *op.SetLocation(newYork).SetTimeNormalize(true))
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2023, 12, 31, 21, 0, 0, 0, newYork), v)
+
+		_, err = ToTimeE("2024-01-01 10:00:00 Asia/Shanghai")
+		assert.Error(t, err)
+		_, err = ToTimeE("2024-01-01 10:00:00 Mars/Olympus", *op)
+		assert.Error(t, err)
+
+		v, err = ToTimeE("2024-01-01 10:00 Asia/Shanghai", *NewOptions().SetTimeZoneNames(true))
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 1, 1, 10, 0, 0, 0, shanghai), v)
+
+		_, err = ToTimeE("2024-01-01 10 Asia/Shanghai", *NewOptions().SetTimeZoneNames(true))
+		assert.Equal(t, errors.Errorf(ErrUnableParseTime, "2024-01-01 10 Asia/Shanghai", timeType).Error(), err.Error())
+	})
+
+	t.Run("Test with field tag", func(t *testing.T) {
+		type meeting struct {
+			Start time.Time  `json:"start" goany:",tz=America/New_York"`
+			End   *time.Time `json:"end" goany:",tz=Asia/Shanghai"`
+			At    time.Time  `json:"at"`
+		}
+		var out meeting
+		in := map[string]interface{}{"start": "2024-01-01 10:00:00", "end": time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), "at": "2024-01-01 10:00:00"}
+		err := ToAny(in, &out)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 1, 1, 10, 0, 0, 0, newYork), out.Start)
+		assert.Equal(t, time.Date(2024, 1, 1, 8, 0, 0, 0, shanghai), *out.End)
+		assert.Equal(t, time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), out.At)
+
+		var copied meeting
+		err = ToAny(struct {
+			Start string `json:"start"`
+		}{Start: "2024-06-01 09:00:00"}, &copied)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 6, 1, 9, 0, 0, 0, newYork), copied.Start)
+	})
+
+	t.Run("Test with unknown field zone", func(t *testing.T) {
+		var out struct {
+			Start time.Time `json:"start" goany:",tz=Nowhere/Land"`
+		}
+		err := ToAny(map[string]interface{}{"start": "2024-01-01"}, &out)
+		assert.Equal(t, errors.Errorf(ErrUnknownTimeZone, "Nowhere/Land").Error(), err.Error())
+	})
+}
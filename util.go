@@ -36,3 +36,31 @@ func GetFieldNameByTag(field reflect.StructField, tag string) string {
 	}
 	return tagValue
 }
+
+// tagOption returns the value of a "key=value" option in the TagOptions tag of a field,
+// options follow the first comma like `goany:",tz=UTC,format=2006-01-02"`. The format option is
+// the last one and takes the rest of the tag, so that layouts may hold commas such as time.RFC1123.
+func tagOption(field reflect.StructField, key string) (string, bool) {
+	tagValue := field.Tag.Get(TagOptions)
+	if tagValue == "" {
+		return "", false
+	}
+	parts := strings.Split(tagValue, ",")
+	for i, part := range parts[1:] {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		k = strings.TrimSpace(k)
+		if k == TagTimeFormat {
+			v = strings.Join(append([]string{v}, parts[i+2:]...), ",")
+		}
+		if k == key {
+			return strings.TrimSpace(v), true
+		}
+		if k == TagTimeFormat {
+			break
+		}
+	}
+	return "", false
+}
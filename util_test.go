@@ -3,6 +3,9 @@ package goany
 import (
 	"reflect"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 type TestStruct struct {
@@ -47,3 +50,41 @@ func TestGetFieldNameByTag(t *testing.T) {
 		assertFieldName(t, typeOfTestStruct.Field(1), "", "Age")
 	})
 }
+
+func TestTagOption(t *testing.T) {
+	type event struct {
+		At    time.Time `json:"at" goany:",tz=UTC,format=Mon, 02 Jan 2006 15:04:05 MST"`
+		Day   time.Time `json:"day" goany:",format=2006-01-02,tz=UTC"`
+		Plain time.Time `json:"plain"`
+	}
+	typ := reflect.TypeOf(event{})
+
+	t.Run("format with commas", func(t *testing.T) {
+		format, ok := tagOption(typ.Field(0), TagTimeFormat)
+		assert.True(t, ok)
+		assert.Equal(t, time.RFC1123, format)
+		zone, ok := tagOption(typ.Field(0), TagTimeZone)
+		assert.True(t, ok)
+		assert.Equal(t, "UTC", zone)
+	})
+
+	t.Run("format takes the rest of the tag", func(t *testing.T) {
+		format, ok := tagOption(typ.Field(1), TagTimeFormat)
+		assert.True(t, ok)
+		assert.Equal(t, "2006-01-02,tz=UTC", format)
+		_, ok = tagOption(typ.Field(1), TagTimeZone)
+		assert.False(t, ok)
+	})
+
+	t.Run("no options", func(t *testing.T) {
+		_, ok := tagOption(typ.Field(2), TagTimeFormat)
+		assert.False(t, ok)
+	})
+
+	t.Run("decode with comma layout", func(t *testing.T) {
+		var out event
+		err := ToAny(map[string]interface{}{"at": "Tue, 02 Jan 2024 03:04:05 UTC"}, &out)
+		assert.NoError(t, err)
+		assert.True(t, out.At.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+	})
+}